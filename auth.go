@@ -1,8 +1,9 @@
 package ffs
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -24,8 +25,42 @@ GetAuthData - Function to get the Authentication data (mainly the authentication
 The authentication token is good for up to 1 hour before it expires
 */
 func GetAuthData(uri string, username string, password string) (*AuthData, error) {
+	return GetAuthDataContext(context.Background(), uri, username, password)
+}
+
+// GetAuthDataContext - GetAuthData which can be cancelled through ctx
+func GetAuthDataContext(ctx context.Context, uri string, username string, password string) (*AuthData, error) {
+	return NewClient(WithAuthURL(uri)).GetAuthDataContext(ctx, username, password)
+}
+
+/*
+GetAuthData - Get the Authentication data from the client's auth URL
+The authentication token is good for up to 1 hour before it expires
+*/
+func (c *Client) GetAuthData(username string, password string) (*AuthData, error) {
+	return c.GetAuthDataContext(context.Background(), username, password)
+}
+
+// GetAuthDataContext - GetAuthData which can be cancelled through ctx, transient failures are retried according to the client's retry policy
+func (c *Client) GetAuthDataContext(ctx context.Context, username string, password string) (*AuthData, error) {
+	var authData *AuthData
+
+	err := c.withRetry(ctx, "Authentication Token GET", func() (err error) {
+		authData, err = c.getAuthData(ctx, username, password)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return authData, nil
+}
+
+// getAuthData makes a single request for Authentication data
+func (c *Client) getAuthData(ctx context.Context, username string, password string) (*AuthData, error) {
 	//Build HTTP GET request
-	req, err := http.NewRequest("POST", uri, nil)
+	req, err := c.newRequest(ctx, "POST", c.authURL, nil)
 
 	//Return nil and err if Building of HTTP GET request fails
 	if err != nil {
@@ -38,7 +73,7 @@ func GetAuthData(uri string, username string, password string) (*AuthData, error
 	req.Header.Set("Accept", "application/json")
 
 	//Make the HTTP Call
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.do(ctx, req)
 
 	//Return nil and err if Building of HTTP GET request fails
 	if err != nil {
@@ -49,7 +84,7 @@ func GetAuthData(uri string, username string, password string) (*AuthData, error
 
 	//Return err if status code != 200
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("Error with Authentication Token GET: " + resp.Status)
+		return nil, newHTTPError("Authentication Token GET", resp)
 	}
 
 	//Create AuthData variable
@@ -57,10 +92,19 @@ func GetAuthData(uri string, username string, password string) (*AuthData, error
 
 	respData := resp.Body
 
-	responseBytes, _ := ioutil.ReadAll(respData)
+	responseBytes, err := ioutil.ReadAll(respData)
+
+	//Return ctx.Err() if the body read was interrupted by cancellation
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, err
+	}
 
-	if strings.Contains(string(responseBytes), "Service Under Maintenance") {
-		return nil, errors.New("error: auth api service is under maintenance")
+	if strings.Contains(string(responseBytes), maintenanceMarker) {
+		return nil, fmt.Errorf("error: auth %w", ErrMaintenance)
 	}
 
 	//Decode the resp.Body into authData variable
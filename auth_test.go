@@ -6,6 +6,8 @@ import (
 )
 
 func TestGetAuthData(t *testing.T) {
+	skipWithoutCredentials(t)
+
 	authData, err := GetAuthData(authUri, username, password)
 
 	if err != nil {
@@ -0,0 +1,295 @@
+package ffs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Code42 FFS Client
+
+// Default Code42 endpoints, used when a Client is created without the matching option
+const (
+	DefaultAuthURL = "https://www.crashplan.com/c42api/v3/auth/jwt?useBody=true"
+	DefaultFFSURL  = "https://forensicsearch-default.prod.ffs.us2.code42.com/forensic-search/queryservice/api/v1/fileevent"
+)
+
+/*
+Client - Reusable Crashplan FFS API client
+Owns the http.Client, the endpoint URLs and the request settings used by every call,
+so timeouts, proxies, TLS settings and the User-Agent can be set once per integration.
+A Client is safe for concurrent use.
+*/
+type Client struct {
+	httpClient  *http.Client
+	authURL     string
+	oauthURL    string
+	ffsURL      string
+	exportURL   string
+	userAgent   string
+	logger      *log.Logger
+	tokenSource TokenSource
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
+
+	unknownFields *unknownFieldTracker
+	validateQuery bool
+	maxFilters    int
+}
+
+// Option - Functional option used to configure a Client in NewClient
+type Option func(*Client)
+
+// WithHTTPClient - Use httpClient for every request instead of http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAuthURL - Set the URL which provides the API token
+func WithAuthURL(uri string) Option {
+	return func(c *Client) {
+		c.authURL = uri
+	}
+}
+
+/*
+WithFFSURL - Set the URL of the FFS file event search endpoint
+Unless WithExportURL is also given, the CSV export endpoint is derived from it by appending /export
+*/
+func WithFFSURL(uri string) Option {
+	return func(c *Client) {
+		c.ffsURL = uri
+	}
+}
+
+// WithExportURL - Set the URL of the FFS CSV export endpoint
+func WithExportURL(uri string) Option {
+	return func(c *Client) {
+		c.exportURL = uri
+	}
+}
+
+// WithUserAgent - Set the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithLogger - Log debugging information (such as page tokens) to logger, nothing is logged when unset
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+/*
+WithTokenSource - Authorize requests with tokens from tokenSource
+The token source is used whenever a call is given an AuthData without an AccessToken.
+If it also has an Invalidate(*AuthData) method (like CachingTokenSource) a 401 from the FFS endpoints
+invalidates the token and the request is retried once with a fresh one.
+*/
+func WithTokenSource(tokenSource TokenSource) Option {
+	return func(c *Client) {
+		c.tokenSource = tokenSource
+	}
+}
+
+// NewClient - Create a Client, any setting not given as an Option falls back to the package defaults
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient:  http.DefaultClient,
+		authURL:     DefaultAuthURL,
+		oauthURL:    DefaultOAuthURL,
+		retryPolicy: DefaultRetryPolicy,
+		rateLimiter: DefaultRateLimiter,
+		ffsURL:      DefaultFFSURL,
+		maxFilters:  MaxFiltersPerQuery,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+
+	return c
+}
+
+// csvExportURL returns the CSV export endpoint, derived from the search endpoint if not set explicitly
+func (c *Client) csvExportURL() string {
+	if c.exportURL != "" {
+		return c.exportURL
+	}
+
+	return strings.TrimSuffix(c.ffsURL, "/") + "/export"
+}
+
+// newRequest builds a request bound to ctx with the headers shared by every call
+func (c *Client) newRequest(ctx context.Context, method string, uri string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, uri, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return req, nil
+}
+
+/*
+do sends req once the rate limiter allows it
+ctx.Err() is returned instead of the transport error if the request was cancelled
+*/
+func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if c.rateLimiter != nil {
+		err := c.rateLimiter.Wait(ctx)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// invalidator is implemented by token sources which can drop a rejected token
+type invalidator interface {
+	Invalidate(authData *AuthData)
+}
+
+// authorize returns authData if it carries a token, otherwise a token from the client's token source
+func (c *Client) authorize(ctx context.Context, authData AuthData) (*AuthData, bool, error) {
+	if authData.AccessToken != "" {
+		return &authData, false, nil
+	}
+
+	if c.tokenSource == nil {
+		return nil, false, errors.New("authData cannot be nil")
+	}
+
+	token, err := c.tokenSource.Token(ctx)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return token, true, nil
+}
+
+/*
+postQuery POSTs query to uri and returns the response once it has a 200 status, the caller must close the body
+Transient failures are retried according to the client's retry policy. Queries over the client's filter limit
+are not sent, only JsonFileEvents, GetJsonFileEvents and ExportWindowedJsonFileEvents split them.
+*/
+func (c *Client) postQuery(ctx context.Context, uri string, authData AuthData, query Query) (*http.Response, error) {
+	if c.maxFilters > 0 && filterCount(query) > c.maxFilters {
+		return nil, fmt.Errorf("%w: query has %d filters, more than the %d allowed per query, only JsonFileEvents, GetJsonFileEvents and ExportWindowedJsonFileEvents split queries", ErrTooManyFilters, filterCount(query), c.maxFilters)
+	}
+
+	if c.validateQuery {
+		if problems := query.Validate(); problems != nil {
+			return nil, &QueryProblemsError{Problems: problems}
+		}
+	}
+
+	//Validate jsonQuery is valid JSON
+	ffsQuery, err := json.Marshal(query)
+	if err != nil {
+		return nil, errors.New("jsonQuery is not in a valid json format")
+	}
+
+	var resp *http.Response
+
+	err = c.withRetry(ctx, "gathering file events POST", func() (err error) {
+		resp, err = c.postQueryAttempt(ctx, uri, authData, ffsQuery)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+/*
+postQueryAttempt makes a single POST of ffsQuery to uri
+A 401 for a token from the client's token source invalidates the token and retries once
+*/
+func (c *Client) postQueryAttempt(ctx context.Context, uri string, authData AuthData, ffsQuery []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		token, fromSource, err := c.authorize(ctx, authData)
+
+		if err != nil {
+			return nil, err
+		}
+
+		//Query uri with authData API token and jsonQuery body
+		req, err := c.newRequest(ctx, "POST", uri, bytes.NewReader(ffsQuery))
+
+		//Handle request errors
+		if err != nil {
+			return nil, err
+		}
+
+		//Set request headers
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+		//Get Response
+		resp, err := c.do(ctx, req)
+
+		//Handle response errors
+		if err != nil {
+			return nil, err
+		}
+
+		//Refresh a rejected token from the token source once
+		if resp.StatusCode == http.StatusUnauthorized && fromSource && attempt == 0 {
+			if inv, ok := c.tokenSource.(invalidator); ok {
+				resp.Body.Close()
+				inv.Invalidate(token)
+				continue
+			}
+		}
+
+		//Make sure http status code is 200
+		if resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			return nil, newHTTPError("gathering file events POST", resp)
+		}
+
+		return resp, nil
+	}
+}
+
+// logf writes to the client logger if one is set
+func (c *Client) logf(format string, v ...interface{}) {
+	if c.logger != nil {
+		c.logger.Printf(format, v...)
+	}
+}
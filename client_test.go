@@ -0,0 +1,110 @@
+package ffs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewClientDefaults(t *testing.T) {
+	c := NewClient()
+
+	if c.httpClient != http.DefaultClient {
+		t.Error("expected http.DefaultClient to be used by default")
+	}
+
+	if c.authURL != DefaultAuthURL {
+		t.Error("unexpected auth URL: " + c.authURL)
+	}
+
+	if c.csvExportURL() != DefaultFFSURL+"/export" {
+		t.Error("unexpected export URL: " + c.csvExportURL())
+	}
+
+	c = NewClient(WithFFSURL("https://example.com/fileevent/"), WithExportURL(""))
+
+	if c.csvExportURL() != "https://example.com/fileevent/export" {
+		t.Error("unexpected derived export URL: " + c.csvExportURL())
+	}
+}
+
+func TestClientGetAuthData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+
+		if !ok || user != "user@example.com" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Header.Get("User-Agent") != "ffs-test/1.0" {
+			t.Error("unexpected User-Agent: " + r.Header.Get("User-Agent"))
+		}
+
+		_, _ = w.Write([]byte(`{"access_token":"token","token_type":"bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithHTTPClient(server.Client()), WithAuthURL(server.URL), WithUserAgent("ffs-test/1.0"))
+
+	authData, err := c.GetAuthData("user@example.com", "secret")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if authData.AccessToken != "token" || authData.ExpiresIn == nil || *authData.ExpiresIn != 3600 {
+		t.Errorf("unexpected auth data: %+v", authData)
+	}
+
+	_, err = c.GetAuthData("user@example.com", "wrong")
+
+	if err == nil {
+		t.Error("expected an error for bad credentials")
+	}
+}
+
+func TestClientGetJsonFileEvents(t *testing.T) {
+	pages := map[string]JsonFileEventResponse{
+		"":      {FileEvents: []JsonFileEvent{{EventId: "1"}, {EventId: "2"}}, NextPgToken: "page2"},
+		"page2": {FileEvents: []JsonFileEvent{{EventId: "3"}}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var query Query
+
+		err := json.NewDecoder(r.Body).Decode(&query)
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		_ = json.NewEncoder(w).Encode(pages[query.PgToken])
+	}))
+	defer server.Close()
+
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL))
+
+	events, _, err := c.GetJsonFileEvents(AuthData{AccessToken: "token"}, jsonQuery, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+
+	for _, event := range *events {
+		ids = append(ids, event.EventId)
+	}
+
+	if strings.Join(ids, ",") != "1,2,3" {
+		t.Error("unexpected events: " + strings.Join(ids, ","))
+	}
+}
@@ -0,0 +1,372 @@
+package ffs
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FFS CSV Column Mapping
+
+// csvColumn maps an export column, by header name, to a CsvFileEvent field
+type csvColumn struct {
+	header string
+	field  string
+	parse  func(fileEvent *CsvFileEvent, value string) error
+	format func(fileEvent *CsvFileEvent) string
+}
+
+// Timestamp layouts used by the CSV export
+const (
+	csvEventTimestampLayout = time.RFC3339Nano
+	csvFileTimestampLayout  = "2006-01-02 15:04:05"
+)
+
+// Currently recognized csv columns, in the order Code42 exports them
+var csvColumns = []csvColumn{
+	stringColumn("Event ID", "eventId", func(e *CsvFileEvent) *string { return &e.EventId }),
+	stringColumn("Event type", "eventType", func(e *CsvFileEvent) *EventType { return &e.EventType }),
+	timeColumn("Date Observed (UTC)", "eventTimestamp", csvEventTimestampLayout, func(e *CsvFileEvent) **time.Time { return &e.EventTimestamp }),
+	timeColumn("Date Inserted (UTC)", "insertionTimestamp", csvEventTimestampLayout, func(e *CsvFileEvent) **time.Time { return &e.InsertionTimestamp }),
+	stringColumn("File path", "filePath", func(e *CsvFileEvent) *string { return &e.FilePath }),
+	stringColumn("Filename", "fileName", func(e *CsvFileEvent) *string { return &e.FileName }),
+	stringColumn("File type", "fileType", func(e *CsvFileEvent) *string { return &e.FileType }),
+	stringColumn("File Category", "fileCategory", func(e *CsvFileEvent) *FileCategory { return &e.FileCategory }),
+	stringColumn("Identified Extension Category", "identifiedExtensionCategory", func(e *CsvFileEvent) *FileCategory { return &e.IdentifiedExtensionCategory }),
+	stringColumn("Current Extension Category", "currentExtensionCategory", func(e *CsvFileEvent) *FileCategory { return &e.CurrentExtensionCategory }),
+	intColumn("File size (bytes)", "fileSize", func(e *CsvFileEvent) **int { return &e.FileSize }),
+	listColumn("File Owner", "fileOwner", func(e *CsvFileEvent) *[]string { return &e.FileOwner }),
+	stringColumn("MD5 Hash", "md5Checksum", func(e *CsvFileEvent) *string { return &e.Md5Checksum }),
+	stringColumn("SHA-256 Hash", "sha256Checksum", func(e *CsvFileEvent) *string { return &e.Sha256Checksum }),
+	timeColumn("Create Date", "createdTimestamp", csvFileTimestampLayout, func(e *CsvFileEvent) **time.Time { return &e.CreatedTimestamp }),
+	timeColumn("Modified Date", "modifyTimestamp", csvFileTimestampLayout, func(e *CsvFileEvent) **time.Time { return &e.ModifyTimestamp }),
+	stringColumn("Username", "deviceUsername", func(e *CsvFileEvent) *string { return &e.DeviceUsername }),
+	stringColumn("Device ID", "deviceUid", func(e *CsvFileEvent) *string { return &e.DeviceUid }),
+	stringColumn("User UID", "userUid", func(e *CsvFileEvent) *string { return &e.UserUid }),
+	stringColumn("Hostname", "osHostname", func(e *CsvFileEvent) *string { return &e.OsHostname }),
+	stringColumn("Fully Qualified Domain Name", "domainName", func(e *CsvFileEvent) *string { return &e.DomainName }),
+	publicIpColumn("IP address (public)", "publicIpAddress", func(e *CsvFileEvent) *string { return &e.PublicIpAddress }),
+	listColumn("IP address (private)", "privateIpAddresses", func(e *CsvFileEvent) *[]string { return &e.PrivateIpAddresses }),
+	stringColumn("Actor", "actor", func(e *CsvFileEvent) *string { return &e.Actor }),
+	listColumn("Directory ID", "directoryId", func(e *CsvFileEvent) *[]string { return &e.DirectoryId }),
+	stringColumn("Source", "source", func(e *CsvFileEvent) *Source { return &e.Source }),
+	stringColumn("URL", "url", func(e *CsvFileEvent) *string { return &e.Url }),
+	boolColumn("Shared", "shared", func(e *CsvFileEvent) **bool { return &e.Shared }),
+	listColumn("Shared With Users", "sharedWith", func(e *CsvFileEvent) *[]string { return &e.SharedWith }),
+	listColumn("File exposure changed to", "sharingTypeAdded", func(e *CsvFileEvent) *[]string { return &e.SharingTypeAdded }),
+	stringColumn("Cloud drive ID", "cloudDriveId", func(e *CsvFileEvent) *string { return &e.CloudDriveId }),
+	stringColumn("Detection Source Alias", "detectionSourceAlias", func(e *CsvFileEvent) *string { return &e.DetectionSourceAlias }),
+	stringColumn("File Id", "fileId", func(e *CsvFileEvent) *string { return &e.FileId }),
+	listColumn("Exposure Type", "exposure", func(e *CsvFileEvent) *[]ExposureType { return &e.Exposure }),
+	stringColumn("Process Owner", "processOwner", func(e *CsvFileEvent) *string { return &e.ProcessOwner }),
+	stringColumn("Process Name", "processName", func(e *CsvFileEvent) *string { return &e.ProcessName }),
+	stringColumn("Tab/Window Title", "tabWindowTitle", func(e *CsvFileEvent) *string { return &e.TabWindowTitle }),
+	stringColumn("Tab URL", "tabUrl", func(e *CsvFileEvent) *string { return &e.TabUrl }),
+	listColumn("Table Titles", "tabTitles", func(e *CsvFileEvent) *[]string { return &e.TabTitles }),
+	listColumn("Tab URLs", "tabURLs", func(e *CsvFileEvent) *[]string { return &e.TabURLs }),
+	stringColumn("Removable Media Vendor", "removableMediaVendor", func(e *CsvFileEvent) *string { return &e.RemovableMediaVendor }),
+	stringColumn("Removable Media Name", "removableMediaName", func(e *CsvFileEvent) *string { return &e.RemovableMediaName }),
+	stringColumn("Removable Media Serial Number", "removableMediaSerialNumber", func(e *CsvFileEvent) *string { return &e.RemovableMediaSerialNumber }),
+	intColumn("Removable Media Capacity", "removableMediaCapacity", func(e *CsvFileEvent) **int { return &e.RemovableMediaCapacity }),
+	stringColumn("Removable Media Bus Type", "removableMediaBusType", func(e *CsvFileEvent) *RemovableMediaBusType { return &e.RemovableMediaBusType }),
+	stringColumn("Removable Media Media Name", "removableMediaMediaName", func(e *CsvFileEvent) *string { return &e.RemovableMediaMediaName }),
+	stringColumn("Removable Media Volume Name", "removableMediaVolumeName", func(e *CsvFileEvent) *string { return &e.RemovableMediaVolumeName }),
+	stringColumn("Removable Media Partition Id", "removableMediaPartitionId", func(e *CsvFileEvent) *string { return &e.RemovableMediaPartitionId }),
+	stringColumn("Sync Destination", "syncDestination", func(e *CsvFileEvent) *string { return &e.SyncDestination }),
+	stringColumn("Sync Destination Username", "syncDestinationUsername", func(e *CsvFileEvent) *string { return &e.SyncDestinationUsername }),
+	listColumn("Email DLP Policy Names", "emailDLPPolicyNames", func(e *CsvFileEvent) *[]string { return &e.EmailDLPPolicyNames }),
+	stringColumn("Email DLP Subject", "emailDLPSubject", func(e *CsvFileEvent) *string { return &e.EmailDLPSubject }),
+	stringColumn("Email DLP Sender", "emailDLPSender", func(e *CsvFileEvent) *string { return &e.EmailDLPSender }),
+	stringColumn("Email DLP From", "emailDLPFrom", func(e *CsvFileEvent) *string { return &e.EmailDLPFrom }),
+	listColumn("Email DLP Recipients", "emailDLPRecipients", func(e *CsvFileEvent) *[]string { return &e.EmailDLPRecipients }),
+	boolColumn("Outside Active Hours", "outsideActiveHours", func(e *CsvFileEvent) **bool { return &e.OutsideActiveHours }),
+	stringColumn("Identified Extension MIME Type", "identifiedExtensionMimeType", func(e *CsvFileEvent) *string { return &e.IdentifiedExtensionMIMEType }),
+	stringColumn("Current Extension MIME Type", "currentExtensionMimeType", func(e *CsvFileEvent) *string { return &e.CurrentExtensionMIMEType }),
+	boolColumn("Suspicious File Type Mismatch", "suspiciousFileTypeMismatch", func(e *CsvFileEvent) **bool { return &e.SuspiciousFileTypeMismatch }),
+	stringColumn("Print Job Name", "printJobName", func(e *CsvFileEvent) *string { return &e.PrintJobName }),
+	stringColumn("Printer Name", "printerName", func(e *CsvFileEvent) *string { return &e.PrinterName }),
+	stringColumn("Printed Files Backup Path", "printedFilesBackupPath", func(e *CsvFileEvent) *string { return &e.PrintedFilesBackupPath }),
+	stringColumn("Remote Activity", "remoteActivity", func(e *CsvFileEvent) *string { return &e.RemoteActivity }),
+	boolColumn("Trusted", "trusted", func(e *CsvFileEvent) **bool { return &e.Trusted }),
+	stringColumn("Logged in Operating System User", "loggedInOperatingSystemUser", func(e *CsvFileEvent) *string { return &e.LoggedInOperatingSystemUser }),
+	stringColumn("Destination Category", "destinationCategory", func(e *CsvFileEvent) *DestinationCategory { return &e.DestinationCategory }),
+	stringColumn("Destination Name", "destinationName", func(e *CsvFileEvent) *string { return &e.DestinationName }),
+}
+
+// Currently recognized csv headers
+var csvHeaders = columnHeaders(csvColumns)
+
+func columnHeaders(columns []csvColumn) []string {
+	headers := make([]string, len(columns))
+
+	for i, column := range columns {
+		headers[i] = column.header
+	}
+
+	return headers
+}
+
+func stringColumn[T ~string](header string, field string, get func(*CsvFileEvent) *T) csvColumn {
+	return csvColumn{header: header, field: field, parse: func(e *CsvFileEvent, value string) error {
+		*get(e) = T(value)
+		return nil
+	}, format: func(e *CsvFileEvent) string {
+		return string(*get(e))
+	}}
+}
+
+// publicIpColumn strips the leading slash and :0 port the export wraps public IP addresses in, and adds them back when writing
+func publicIpColumn(header string, field string, get func(*CsvFileEvent) *string) csvColumn {
+	return csvColumn{header: header, field: field, parse: func(e *CsvFileEvent, value string) error {
+		*get(e) = strings.Replace(strings.Replace(value, "/", "", -1), ":0", "", -1)
+		return nil
+	}, format: func(e *CsvFileEvent) string {
+		if *get(e) == "" {
+			return ""
+		}
+
+		return "/" + *get(e) + ":0"
+	}}
+}
+
+// listColumn splits comma separated multi-value columns, empty values stay nil
+func listColumn[T ~string](header string, field string, get func(*CsvFileEvent) *[]T) csvColumn {
+	return csvColumn{header: header, field: field, parse: func(e *CsvFileEvent, value string) error {
+		if value != "" {
+			values := strings.Split(value, ",")
+			list := make([]T, len(values))
+
+			for i := range values {
+				list[i] = T(values[i])
+			}
+
+			*get(e) = list
+		}
+		return nil
+	}, format: func(e *CsvFileEvent) string {
+		values := make([]string, len(*get(e)))
+
+		for i, value := range *get(e) {
+			values[i] = string(value)
+		}
+
+		return strings.Join(values, ",")
+	}}
+}
+
+func timeColumn(header string, field string, layout string, get func(*CsvFileEvent) **time.Time) csvColumn {
+	return csvColumn{header: header, field: field, parse: func(e *CsvFileEvent, value string) error {
+		if value == "" {
+			return nil
+		}
+
+		timestamp, err := time.Parse(layout, value)
+
+		if err != nil {
+			return err
+		}
+
+		*get(e) = &timestamp
+		return nil
+	}, format: func(e *CsvFileEvent) string {
+		if *get(e) == nil {
+			return ""
+		}
+
+		return (*get(e)).UTC().Format(layout)
+	}}
+}
+
+func intColumn(header string, field string, get func(*CsvFileEvent) **int) csvColumn {
+	return csvColumn{header: header, field: field, parse: func(e *CsvFileEvent, value string) error {
+		if value == "" {
+			return nil
+		}
+
+		number, err := strconv.Atoi(value)
+
+		if err != nil {
+			return err
+		}
+
+		*get(e) = &number
+		return nil
+	}, format: func(e *CsvFileEvent) string {
+		if *get(e) == nil {
+			return ""
+		}
+
+		return strconv.Itoa(**get(e))
+	}}
+}
+
+func boolColumn(header string, field string, get func(*CsvFileEvent) **bool) csvColumn {
+	return csvColumn{header: header, field: field, parse: func(e *CsvFileEvent, value string) error {
+		if value == "" {
+			return nil
+		}
+
+		boolean, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return err
+		}
+
+		*get(e) = &boolean
+		return nil
+	}, format: func(e *CsvFileEvent) string {
+		if *get(e) == nil {
+			return ""
+		}
+
+		return strconv.FormatBool(**get(e))
+	}}
+}
+
+// trimHeader removes potential eol chars left on the last header
+func trimHeader(name string) string {
+	return strings.TrimRight(name, "\r\n")
+}
+
+/*
+CsvSchemaDrift - Differences between the header of an export and the columns this package knows
+Missing columns are left empty on every event, Added columns are kept in CsvFileEvent.Extras.
+*/
+type CsvSchemaDrift struct {
+	Missing   []string
+	Added     []string
+	Reordered bool
+}
+
+func (d *CsvSchemaDrift) String() string {
+	var parts []string
+
+	if len(d.Missing) > 0 {
+		parts = append(parts, "missing columns: "+strings.Join(d.Missing, ", "))
+	}
+
+	if len(d.Added) > 0 {
+		parts = append(parts, "added columns: "+strings.Join(d.Added, ", "))
+	}
+
+	if d.Reordered {
+		parts = append(parts, "columns reordered")
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// SchemaDriftError - Returned for an export whose columns drifted when a strict schema is required
+type SchemaDriftError struct {
+	Drift CsvSchemaDrift
+}
+
+func (e *SchemaDriftError) Error() string {
+	return "csv export columns changed: " + e.Drift.String()
+}
+
+// csvMapping maps each column index of an export to a known column, or to an extra when unknown
+type csvMapping struct {
+	columns []*csvColumn
+	headers []string
+	drift   *CsvSchemaDrift
+}
+
+// newCsvMapping matches header against columns by name
+func newCsvMapping(header []string, columns []csvColumn) (*csvMapping, error) {
+	byHeader := make(map[string]*csvColumn, len(columns))
+
+	for i := range columns {
+		byHeader[columns[i].header] = &columns[i]
+	}
+
+	mapping := &csvMapping{
+		columns: make([]*csvColumn, len(header)),
+		headers: make([]string, len(header)),
+	}
+
+	var drift CsvSchemaDrift
+	found := make(map[string]bool, len(header))
+	position := 0
+
+	for i, name := range header {
+		name = trimHeader(name)
+		mapping.headers[i] = name
+
+		if found[name] {
+			return nil, errors.New("csv export has duplicate column: " + name)
+		}
+
+		found[name] = true
+
+		column, ok := byHeader[name]
+
+		if !ok {
+			drift.Added = append(drift.Added, name)
+			continue
+		}
+
+		mapping.columns[i] = column
+
+		//Known columns must appear in the same relative order as in columns
+		for position < len(columns) && columns[position].header != name {
+			position++
+		}
+
+		if position == len(columns) {
+			drift.Reordered = true
+			position = 0
+		}
+	}
+
+	for _, column := range columns {
+		if !found[column.header] {
+			drift.Missing = append(drift.Missing, column.header)
+		}
+	}
+
+	if len(drift.Missing) > 0 || len(drift.Added) > 0 || drift.Reordered {
+		mapping.drift = &drift
+	}
+
+	return mapping, nil
+}
+
+/*
+toCsvFileEvent converts a record using the mapping, unknown columns are kept in Extras
+Every column which fails to parse is left empty and reported as a *ParseError without a line number.
+*/
+func (m *csvMapping) toCsvFileEvent(record []string) (*CsvFileEvent, []*ParseError) {
+	var fileEvent CsvFileEvent
+
+	if len(record) != len(m.columns) {
+		return nil, []*ParseError{{Err: errors.New("row has " + strconv.Itoa(len(record)) + " columns, expected " + strconv.Itoa(len(m.columns)))}}
+	}
+
+	var parseErrors []*ParseError
+
+	for i, value := range record {
+		column := m.columns[i]
+
+		if column == nil || column.parse == nil {
+			if value != "" {
+				if fileEvent.Extras == nil {
+					fileEvent.Extras = make(map[string]string)
+				}
+
+				fileEvent.Extras[m.headers[i]] = value
+			}
+
+			continue
+		}
+
+		err := column.parse(&fileEvent, value)
+
+		if err != nil {
+			parseErrors = append(parseErrors, &ParseError{Column: column.header, Value: value, Err: err})
+		}
+	}
+
+	return &fileEvent, parseErrors
+}
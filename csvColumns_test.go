@@ -0,0 +1,79 @@
+package ffs
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"testing"
+)
+
+// csvBody builds an export body with an arbitrary header
+func csvBody(header []string, rows ...[]string) *bytes.Buffer {
+	var buf bytes.Buffer
+
+	w := csv.NewWriter(&buf)
+	_ = w.Write(header)
+	_ = w.WriteAll(rows)
+
+	return &buf
+}
+
+func TestCsvFileEventReaderSchemaDrift(t *testing.T) {
+	//Reordered, with "File size (bytes)" removed and a new column added
+	header := []string{"Filename", "Event ID", "Brand New Column", "Table Titles", "Tab URLs"}
+	row := []string{"report.xlsx", "abc", "surprise", "Inbox,Drafts", "https://a,https://b"}
+
+	reader, err := NewCsvFileEventReader(csvBody(header, row))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drift := reader.Drift()
+
+	if drift == nil || !drift.Reordered || len(drift.Added) != 1 || drift.Added[0] != "Brand New Column" || len(drift.Missing) != len(csvHeaders)-4 {
+		t.Fatalf("unexpected drift: %+v", drift)
+	}
+
+	if !reader.Next() {
+		t.Fatal(reader.Err())
+	}
+
+	event := reader.Event()
+
+	if event.EventId != "abc" || event.FileName != "report.xlsx" || event.FileSize != nil {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	if event.Extras["Brand New Column"] != "surprise" {
+		t.Errorf("unexpected extras: %v", event.Extras)
+	}
+
+	if len(event.TabTitles) != 2 || len(event.TabURLs) != 2 || event.Exposure != nil {
+		t.Errorf("tab columns mapped to the wrong fields: %v %v %v", event.TabTitles, event.TabURLs, event.Exposure)
+	}
+}
+
+func TestCsvFileEventReaderStrictSchema(t *testing.T) {
+	_, err := NewCsvFileEventReader(csvBody(append(csvHeaders, "Brand New Column")), WithStrictSchema())
+
+	var driftErr *SchemaDriftError
+
+	if !errors.As(err, &driftErr) || len(driftErr.Drift.Added) != 1 {
+		t.Errorf("expected a SchemaDriftError, got %v", err)
+	}
+
+	reader, err := NewCsvFileEventReader(csvBody(csvHeaders), WithStrictSchema())
+
+	if err != nil || reader.Drift() != nil {
+		t.Errorf("expected the current columns to pass strict mode, got %v, %+v", err, reader.Drift())
+	}
+}
+
+func TestCsvFileEventReaderDuplicateColumn(t *testing.T) {
+	_, err := NewCsvFileEventReader(csvBody([]string{"Event ID", "Event ID"}))
+
+	if err == nil {
+		t.Error("expected an error for duplicate columns")
+	}
+}
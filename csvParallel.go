@@ -0,0 +1,137 @@
+package ffs
+
+import (
+	"sync"
+)
+
+// FFS CSV Parallel Conversion
+
+// csvRowsPerWorker - Rows each worker converts per batch, bounds the rows held in memory to workers * csvRowsPerWorker
+const csvRowsPerWorker = 256
+
+// convertedCsvRow is a converted record waiting to be returned by CsvFileEventReader.Next
+type convertedCsvRow struct {
+	fileEvent   *CsvFileEvent
+	parseErrors []*ParseError
+}
+
+/*
+WithWorkers - Convert rows on up to workers goroutines, events are still returned in the order of the export
+Rows are read in batches of 256 per worker on a separate goroutine while the previous batch is converted,
+at most three batches are held in memory. 0 or 1 converts each row as it is read.
+*/
+func WithWorkers(workers int) CsvReaderOption {
+	return func(cfg *csvReaderConfig) {
+		cfg.workers = workers
+	}
+}
+
+// csvBatch is a batch of records read ahead of their conversion, err is the error which ended reading
+type csvBatch struct {
+	records [][]string
+	lines   []int
+	err     error
+}
+
+// readBatch reads up to size records
+func (r *CsvFileEventReader) readBatch(size int) csvBatch {
+	batch := csvBatch{records: make([][]string, 0, size), lines: make([]int, 0, size)}
+
+	for len(batch.records) < size {
+		record, err := r.reader.Read()
+
+		if err != nil {
+			batch.err = err
+			break
+		}
+
+		line, _ := r.reader.FieldPos(0)
+
+		batch.records = append(batch.records, record)
+		batch.lines = append(batch.lines, line)
+	}
+
+	return batch
+}
+
+// readAhead reads batches on its own goroutine until reading fails or stop is closed, the last batch sent carries the error
+func (r *CsvFileEventReader) readAhead(size int, batches chan<- csvBatch, stop <-chan struct{}) {
+	for {
+		batch := r.readBatch(size)
+
+		select {
+		case batches <- batch:
+		case <-stop:
+			return
+		}
+
+		if batch.err != nil {
+			return
+		}
+	}
+}
+
+/*
+fill converts the next batch of records into r.rows
+With workers the records are read ahead on another goroutine, so reading the next batch overlaps converting this one.
+*/
+func (r *CsvFileEventReader) fill() {
+	var batch csvBatch
+
+	if r.workers <= 1 {
+		batch = r.readBatch(1)
+	} else {
+		if r.batches == nil {
+			r.batches = make(chan csvBatch, 1)
+			r.stop = make(chan struct{})
+
+			go r.readAhead(r.workers*csvRowsPerWorker, r.batches, r.stop)
+		}
+
+		batch = <-r.batches
+	}
+
+	if batch.err != nil {
+		r.readErr = batch.err
+	}
+
+	records, lines := batch.records, batch.lines
+	rows := make([]convertedCsvRow, len(records))
+
+	//Each row is written to its own index, so the output keeps the order of the export
+	convert := func(start int, end int) {
+		for i := start; i < end; i++ {
+			fileEvent, parseErrors := r.mapping.toCsvFileEvent(records[i])
+
+			for _, parseError := range parseErrors {
+				parseError.Line = lines[i]
+			}
+
+			rows[i] = convertedCsvRow{fileEvent: fileEvent, parseErrors: parseErrors}
+		}
+	}
+
+	if r.workers <= 1 || len(records) <= csvRowsPerWorker {
+		convert(0, len(records))
+	} else {
+		var wg sync.WaitGroup
+
+		for start := 0; start < len(records); start += csvRowsPerWorker {
+			end := start + csvRowsPerWorker
+
+			if end > len(records) {
+				end = len(records)
+			}
+
+			wg.Add(1)
+			go func(start int, end int) {
+				defer wg.Done()
+				convert(start, end)
+			}(start, end)
+		}
+
+		wg.Wait()
+	}
+
+	r.rows = rows
+}
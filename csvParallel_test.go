@@ -0,0 +1,129 @@
+package ffs
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// benchmarkCsvExport builds an export of n realistic rows
+func benchmarkCsvExport(n int) []byte {
+	rows := make([][]string, n)
+
+	for i := range rows {
+		rows[i] = csvRow(map[string]string{
+			"Event ID":             strconv.Itoa(i),
+			"Event type":           "MODIFIED",
+			"Date Observed (UTC)":  "2020-01-01T00:00:00.123Z",
+			"Date Inserted (UTC)":  "2020-01-01T00:05:00.456Z",
+			"File path":            "C:/Users/someone/Documents/",
+			"Filename":             "report-" + strconv.Itoa(i) + ".xlsx",
+			"File size (bytes)":    strconv.Itoa(i * 1024),
+			"File Owner":           "someone,someone-else",
+			"Create Date":          "2019-12-31 12:00:00",
+			"Modified Date":        "2020-01-01 00:00:00",
+			"IP address (public)":  "/203.0.113.10:0",
+			"IP address (private)": "10.0.0.1,192.168.1.20",
+			"Shared":               "false",
+			"Exposure Type":        "RemovableMedia,ApplicationRead",
+			"Trusted":              "true",
+		})
+	}
+
+	return csvExport(rows...)
+}
+
+func TestCsvFileEventReaderWorkersKeepOrder(t *testing.T) {
+	rows := make([][]string, 5000)
+
+	for i := range rows {
+		rows[i] = csvRow(map[string]string{"Event ID": strconv.Itoa(i), "File size (bytes)": strconv.Itoa(i)})
+	}
+
+	//A bad value late in the export, it must be reported with its own line number
+	rows[4000] = csvRow(map[string]string{"Event ID": "4000", "File size (bytes)": "bad"})
+
+	reader, err := NewCsvFileEventReader(bytes.NewReader(csvExport(rows...)), WithWorkers(4), WithParseMode(ParseSkipInvalid))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := 0
+
+	for reader.Next() {
+		if expected == 4000 {
+			expected++
+		}
+
+		if reader.Event().EventId != strconv.Itoa(expected) {
+			t.Fatalf("expected event %d, got %s", expected, reader.Event().EventId)
+		}
+
+		expected++
+	}
+
+	if reader.Err() != nil || expected != len(rows) {
+		t.Fatalf("expected %d events, got %d: %v", len(rows), expected, reader.Err())
+	}
+
+	if len(reader.ParseErrors()) != 1 || reader.ParseErrors()[0].Line != 4002 {
+		t.Errorf("unexpected parse errors: %v", reader.ParseErrors())
+	}
+}
+
+func TestCsvFileEventReaderCloseStopsReadAhead(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	reader, err := NewCsvFileEventReader(bytes.NewReader(benchmarkCsvExport(20000)), WithWorkers(2))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reader.Next() || reader.Event().EventId != "0" {
+		t.Fatalf("unexpected first event %+v: %v", reader.Event(), reader.Err())
+	}
+
+	_ = reader.Close()
+
+	for i := 0; runtime.NumGoroutine() > before; i++ {
+		if i == 100 {
+			t.Fatalf("read ahead goroutine still running after Close")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func benchmarkCsvFileEventReader(b *testing.B, workers int) {
+	export := benchmarkCsvExport(50000)
+
+	b.SetBytes(int64(len(export)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		reader, err := NewCsvFileEventReader(bytes.NewReader(export), WithWorkers(workers))
+
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for reader.Next() {
+		}
+
+		if reader.Err() != nil {
+			b.Fatal(reader.Err())
+		}
+	}
+}
+
+func BenchmarkCsvFileEventReaderSequential(b *testing.B) {
+	benchmarkCsvFileEventReader(b, 1)
+}
+
+func BenchmarkCsvFileEventReaderParallel(b *testing.B) {
+	benchmarkCsvFileEventReader(b, runtime.GOMAXPROCS(0))
+}
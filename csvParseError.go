@@ -0,0 +1,50 @@
+package ffs
+
+import (
+	"strconv"
+)
+
+// FFS CSV Parse Errors
+
+/*
+ParseError - A CSV value which could not be converted
+Line is the line of the export the value is on, Column its header name and Value the raw text.
+Column and Value are empty when the whole row is unusable, for example because it has the wrong number of columns.
+*/
+type ParseError struct {
+	Line   int
+	Column string
+	Value  string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Column == "" {
+		return "csv line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+	}
+
+	return "csv line " + strconv.Itoa(e.Line) + ", column " + strconv.Quote(e.Column) + ": cannot parse " + strconv.Quote(e.Value) + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseMode - How a CsvFileEventReader handles rows with values that cannot be parsed
+type ParseMode int
+
+const (
+	// ParseStrict - Stop at the first bad value, Err returns its *ParseError
+	ParseStrict ParseMode = iota
+	// ParseSkipInvalid - Skip rows with bad values, the errors are collected in ParseErrors
+	ParseSkipInvalid
+	// ParsePartial - Keep rows with bad values, leaving those fields empty, the errors are collected in ParseErrors
+	ParsePartial
+)
+
+// WithParseMode - Set how rows with values that cannot be parsed are handled, the default is ParseStrict
+func WithParseMode(mode ParseMode) CsvReaderOption {
+	return func(cfg *csvReaderConfig) {
+		cfg.parseMode = mode
+	}
+}
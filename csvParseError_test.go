@@ -0,0 +1,86 @@
+package ffs
+
+import (
+	"errors"
+	"testing"
+)
+
+func badCsvRows() []([]string) {
+	return [][]string{
+		csvRow(map[string]string{"Event ID": "1", "File size (bytes)": "10"}),
+		csvRow(map[string]string{"Event ID": "2", "File size (bytes)": "ten", "Trusted": "maybe", "Filename": "a.txt"}),
+		{"3", "too", "short"},
+		csvRow(map[string]string{"Event ID": "4"}),
+	}
+}
+
+func TestCsvFileEventReaderStrict(t *testing.T) {
+	reader, err := NewCsvFileEventReader(csvBody(csvHeaders, badCsvRows()...))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+
+	for reader.Next() {
+		n++
+	}
+
+	var parseErr *ParseError
+
+	if !errors.As(reader.Err(), &parseErr) {
+		t.Fatalf("expected a *ParseError, got %v", reader.Err())
+	}
+
+	if n != 1 || parseErr.Line != 3 || parseErr.Column != "File size (bytes)" || parseErr.Value != "ten" {
+		t.Errorf("unexpected result: %d events, %+v", n, parseErr)
+	}
+}
+
+func TestCsvFileEventReaderLenient(t *testing.T) {
+	for _, test := range []struct {
+		mode ParseMode
+		ids  string
+	}{
+		{ParseSkipInvalid, "1,4"},
+		{ParsePartial, "1,2,4"},
+	} {
+		reader, err := NewCsvFileEventReader(csvBody(csvHeaders, badCsvRows()...), WithParseMode(test.mode))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := ""
+
+		for reader.Next() {
+			event := reader.Event()
+
+			if event.EventId == "2" && (event.FileName != "a.txt" || event.FileSize != nil || event.Trusted != nil) {
+				t.Errorf("unexpected partial event: %+v", event)
+			}
+
+			if ids != "" {
+				ids += ","
+			}
+
+			ids += event.EventId
+		}
+
+		if reader.Err() != nil {
+			t.Fatal(reader.Err())
+		}
+
+		if ids != test.ids {
+			t.Errorf("mode %d: expected events %s, got %s", test.mode, test.ids, ids)
+		}
+
+		//Two bad values on line 3 and the short row on line 4
+		parseErrors := reader.ParseErrors()
+
+		if len(parseErrors) != 3 || parseErrors[1].Column != "Trusted" || parseErrors[2].Line != 4 || parseErrors[2].Column != "" {
+			t.Errorf("mode %d: unexpected parse errors: %v", test.mode, parseErrors)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package ffs
+
+import (
+	"errors"
+	"sync"
+)
+
+// FFS CSV Schema Versions
+
+/*
+CsvSchema - A version of the Code42 CSV export layout
+Headers lists the export's columns in order, Fields maps a header to the CsvFileEvent field it fills,
+named by the field's json tag (e.g. "Event ID" -> "eventId"). Headers without a field are kept in CsvFileEvent.Extras.
+The registry hands out copies, changing a returned schema does not change the registered version.
+*/
+type CsvSchema struct {
+	Version string
+	Headers []string
+	Fields  map[string]string
+}
+
+// Built in schema versions
+const (
+	// CsvSchemaV1 - The export layout before the Destination Category and Destination Name columns
+	CsvSchemaV1 = "v1"
+	// CsvSchemaV2 - The current export layout, ending with Destination Category and Destination Name
+	CsvSchemaV2 = "v2"
+	// CurrentCsvSchemaVersion - The version CSV writers use by default
+	CurrentCsvSchemaVersion = CsvSchemaV2
+)
+
+// csvFields maps a CsvFileEvent json field name to its column
+var csvFields = columnFields(csvColumns)
+
+var (
+	csvSchemasMu sync.RWMutex
+	csvSchemas   = []*CsvSchema{
+		columnSchema(CsvSchemaV1, csvColumns[:len(csvColumns)-2]),
+		columnSchema(CsvSchemaV2, csvColumns),
+	}
+)
+
+func columnFields(columns []csvColumn) map[string]*csvColumn {
+	fields := make(map[string]*csvColumn, len(columns))
+
+	for i := range columns {
+		fields[columns[i].field] = &columns[i]
+	}
+
+	return fields
+}
+
+// columnSchema builds a schema whose headers are the column headers of columns
+func columnSchema(version string, columns []csvColumn) *CsvSchema {
+	schema := &CsvSchema{
+		Version: version,
+		Headers: columnHeaders(columns),
+		Fields:  make(map[string]string, len(columns)),
+	}
+
+	for _, column := range columns {
+		schema.Fields[column.header] = column.field
+	}
+
+	return schema
+}
+
+/*
+RegisterCsvSchema - Add a schema version to the versions the CSV reader detects
+Pass the returned schema to WithWriterSchema to write it. An error is returned for a version
+which already exists, duplicate headers, or a field which is not a CsvFileEvent field.
+*/
+func RegisterCsvSchema(schema CsvSchema) (*CsvSchema, error) {
+	if schema.Version == "" {
+		return nil, errors.New("csv schema version cannot be empty")
+	}
+
+	registered := schema.clone()
+
+	_, err := registered.columns()
+
+	if err != nil {
+		return nil, err
+	}
+
+	csvSchemasMu.Lock()
+	defer csvSchemasMu.Unlock()
+
+	for _, existing := range csvSchemas {
+		if existing.Version == schema.Version {
+			return nil, errors.New("csv schema version " + schema.Version + " is already registered")
+		}
+	}
+
+	csvSchemas = append(csvSchemas, registered)
+
+	return registered.clone(), nil
+}
+
+// clone copies s, so registered schemas cannot be changed through the schemas handed out
+func (s CsvSchema) clone() *CsvSchema {
+	clone := &CsvSchema{
+		Version: s.Version,
+		Headers: append([]string(nil), s.Headers...),
+		Fields:  make(map[string]string, len(s.Fields)),
+	}
+
+	for header, field := range s.Fields {
+		clone.Fields[header] = field
+	}
+
+	return clone
+}
+
+// CsvSchemas - Copies of the registered schema versions, oldest first
+func CsvSchemas() []*CsvSchema {
+	csvSchemasMu.RLock()
+	defer csvSchemasMu.RUnlock()
+
+	schemas := make([]*CsvSchema, len(csvSchemas))
+
+	for i, schema := range csvSchemas {
+		schemas[i] = schema.clone()
+	}
+
+	return schemas
+}
+
+// LookupCsvSchema - A copy of the registered schema with version, nil if there is none
+func LookupCsvSchema(version string) *CsvSchema {
+	csvSchemasMu.RLock()
+	defer csvSchemasMu.RUnlock()
+
+	for _, schema := range csvSchemas {
+		if schema.Version == version {
+			return schema.clone()
+		}
+	}
+
+	return nil
+}
+
+/*
+DetectCsvSchema - Find the registered schema an export header belongs to, returning a copy of it
+exact reports whether header matches the schema's headers exactly. Otherwise the schema sharing the
+most headers is returned, preferring newer versions on ties, and the export drifted from it.
+*/
+func DetectCsvSchema(header []string) (schema *CsvSchema, exact bool) {
+	schemas := CsvSchemas()
+	names := make(map[string]bool, len(header))
+
+	for _, name := range header {
+		names[trimHeader(name)] = true
+	}
+
+	best := -1
+
+	for i := len(schemas) - 1; i >= 0; i-- {
+		candidate := schemas[i]
+
+		if sameHeaders(header, candidate.Headers) {
+			return candidate, true
+		}
+
+		matched := 0
+
+		for _, name := range candidate.Headers {
+			if names[name] {
+				matched++
+			}
+		}
+
+		if matched > best {
+			schema, best = candidate, matched
+		}
+	}
+
+	return schema, false
+}
+
+func sameHeaders(header []string, headers []string) bool {
+	if len(header) != len(headers) {
+		return false
+	}
+
+	for i := range header {
+		if trimHeader(header[i]) != headers[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// columns returns the schema's columns in header order, with the parse and format functions of their fields
+func (s *CsvSchema) columns() ([]csvColumn, error) {
+	columns := make([]csvColumn, 0, len(s.Headers))
+	seen := make(map[string]bool, len(s.Headers))
+
+	for _, header := range s.Headers {
+		if seen[header] {
+			return nil, errors.New("csv schema " + s.Version + " has duplicate header: " + header)
+		}
+
+		seen[header] = true
+
+		field, ok := s.Fields[header]
+
+		//Columns without a field are expected, but kept in Extras
+		if !ok {
+			columns = append(columns, csvColumn{header: header})
+			continue
+		}
+
+		column, ok := csvFields[field]
+
+		if !ok {
+			return nil, errors.New("csv schema " + s.Version + " maps " + header + " to unknown field: " + field)
+		}
+
+		columns = append(columns, csvColumn{header: header, field: field, parse: column.parse, format: column.format})
+	}
+
+	for header := range s.Fields {
+		if !seen[header] {
+			return nil, errors.New("csv schema " + s.Version + " maps a field to missing header: " + header)
+		}
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,122 @@
+package ffs
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDetectCsvSchema(t *testing.T) {
+	v1 := LookupCsvSchema(CsvSchemaV1)
+
+	if v1 == nil || len(v1.Headers) != len(csvHeaders)-2 {
+		t.Fatalf("unexpected v1 schema: %+v", v1)
+	}
+
+	reader, err := NewCsvFileEventReader(csvBody(v1.Headers), WithStrictSchema())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(reader.Schema(), v1) || reader.Drift() != nil {
+		t.Errorf("expected an exact v1 match, got %v with drift %+v", reader.Schema().Version, reader.Drift())
+	}
+
+	schema, exact := DetectCsvSchema(csvHeaders)
+
+	if schema.Version != CsvSchemaV2 || !exact {
+		t.Errorf("expected an exact v2 match, got %s %t", schema.Version, exact)
+	}
+
+	schema, exact = DetectCsvSchema(append(csvHeaders[:3:3], "Brand New Column"))
+
+	if schema.Version != CsvSchemaV2 || exact {
+		t.Errorf("expected an inexact v2 match, got %s %t", schema.Version, exact)
+	}
+}
+
+func TestRegisterCsvSchema(t *testing.T) {
+	schema, err := RegisterCsvSchema(CsvSchema{
+		Version: "test-renamed",
+		Headers: []string{"Event Identifier", "Name", "Size", "Risk Score"},
+		Fields: map[string]string{
+			"Event Identifier": "eventId",
+			"Name":             "fileName",
+			"Size":             "fileSize",
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		csvSchemasMu.Lock()
+		csvSchemas = csvSchemas[:len(csvSchemas)-1]
+		csvSchemasMu.Unlock()
+	})
+
+	if !reflect.DeepEqual(LookupCsvSchema("test-renamed"), schema) {
+		t.Error("registered schema was not found")
+	}
+
+	//Changing a returned schema leaves the registered one alone
+	LookupCsvSchema("test-renamed").Fields["Risk Score"] = "riskScore"
+	CsvSchemas()[len(CsvSchemas())-1].Headers[0] = "Changed"
+
+	if !reflect.DeepEqual(LookupCsvSchema("test-renamed"), schema) {
+		t.Error("registered schema was changed through a returned copy")
+	}
+
+	size := 12
+	event := CsvFileEvent{EventId: "a", FileName: "report.xlsx", FileSize: &size, Extras: map[string]string{"Risk Score": "9"}}
+
+	var buf bytes.Buffer
+
+	writer := NewCsvFileEventWriter(&buf, WithWriterSchema(schema))
+
+	if err := writer.Write(event); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := NewCsvFileEventReader(&buf, WithStrictSchema())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(reader.Schema(), schema) {
+		t.Errorf("detected %s instead of the registered schema", reader.Schema().Version)
+	}
+
+	if !reader.Next() {
+		t.Fatal(reader.Err())
+	}
+
+	read := reader.Event()
+
+	if read.EventId != "a" || read.FileName != "report.xlsx" || read.FileSize == nil || *read.FileSize != 12 || read.Extras["Risk Score"] != "9" {
+		t.Errorf("unexpected event: %+v", read)
+	}
+}
+
+func TestRegisterCsvSchemaErrors(t *testing.T) {
+	tests := map[string]CsvSchema{
+		"no version":     {Headers: []string{"Event ID"}},
+		"existing":       {Version: CsvSchemaV2},
+		"duplicate":      {Version: "test-duplicate", Headers: []string{"Event ID", "Event ID"}},
+		"unknown field":  {Version: "test-unknown", Headers: []string{"Event ID"}, Fields: map[string]string{"Event ID": "nope"}},
+		"missing header": {Version: "test-missing", Headers: []string{"Event ID"}, Fields: map[string]string{"Filename": "fileName"}},
+	}
+
+	for name, schema := range tests {
+		if _, err := RegisterCsvSchema(schema); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package ffs
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+
+	"github.com/spkg/bom"
+)
+
+// FFS CSV Streaming
+
+// csvReaderConfig holds the settings applied by CsvReaderOptions
+type csvReaderConfig struct {
+	strictSchema bool
+	parseMode    ParseMode
+	workers      int
+	schema       *CsvSchema
+}
+
+// CsvReaderOption - Functional option for NewCsvFileEventReader and the Client CSV streaming methods
+type CsvReaderOption func(*csvReaderConfig)
+
+// WithStrictSchema - Fail with a *SchemaDriftError when the export's columns differ from every registered schema version
+func WithStrictSchema() CsvReaderOption {
+	return func(cfg *csvReaderConfig) {
+		cfg.strictSchema = true
+	}
+}
+
+/*
+WithCsvSchema - Read exports with schema instead of detecting the version from the header
+Columns are still matched by name, differences to schema are reported as drift.
+*/
+func WithCsvSchema(schema *CsvSchema) CsvReaderOption {
+	return func(cfg *csvReaderConfig) {
+		cfg.schema = schema
+	}
+}
+
+/*
+CsvFileEventReader - Parses CSV file events one row at a time as they arrive
+Only the current row is held in memory. The byte order mark Code42 prefixes exports with is stripped.
+
+	events, err := client.CsvFileEvents(ctx, authData, query)
+	if err != nil {
+		...
+	}
+	defer events.Close()
+	for events.Next() {
+		process(events.Event())
+	}
+	if err := events.Err(); err != nil {
+		...
+	}
+*/
+type CsvFileEventReader struct {
+	ctx     context.Context
+	reader  *csv.Reader
+	closer  io.Closer
+	mapping *csvMapping
+	schema  *CsvSchema
+	mode    ParseMode
+	workers int
+
+	//rows holds converted rows not yet returned by Next, readErr the error which ended reading them
+	rows    []convertedCsvRow
+	readErr error
+
+	//batches delivers the batches read ahead for the workers, closing stop ends the read ahead goroutine
+	batches chan csvBatch
+	stop    chan struct{}
+
+	event       CsvFileEvent
+	done        bool
+	err         error
+	parseErrors []*ParseError
+}
+
+/*
+NewCsvFileEventReader - Create a CsvFileEventReader reading an FFS CSV export from r
+The header row is read straight away and the registered schema version it belongs to is detected, see DetectCsvSchema.
+Columns are matched by name, see Drift for differences to the schema's columns.
+*/
+func NewCsvFileEventReader(r io.Reader, opts ...CsvReaderOption) (*CsvFileEventReader, error) {
+	return newCsvFileEventReader(context.Background(), r, nil, opts)
+}
+
+func newCsvFileEventReader(ctx context.Context, r io.Reader, closer io.Closer, opts []CsvReaderOption) (*CsvFileEventReader, error) {
+	var cfg csvReaderConfig
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	reader := csv.NewReader(bom.NewReader(r))
+	reader.Comma = ','
+	//Records are only reused when converted one at a time, batches keep them until the workers are done
+	reader.ReuseRecord = cfg.workers <= 1
+	//Rows with the wrong number of columns are reported as a ParseError instead of failing the reader
+	reader.FieldsPerRecord = -1
+
+	csvReader := &CsvFileEventReader{
+		ctx:     ctx,
+		reader:  reader,
+		closer:  closer,
+		mode:    cfg.parseMode,
+		workers: cfg.workers,
+	}
+
+	header, err := reader.Read()
+
+	//An empty export has no header and no events
+	if err == io.EOF {
+		csvReader.done = true
+		return csvReader, nil
+	}
+
+	if err != nil {
+		return nil, csvReader.wrapErr(err)
+	}
+
+	csvReader.schema = cfg.schema
+
+	if csvReader.schema == nil {
+		csvReader.schema, _ = DetectCsvSchema(header)
+	}
+
+	columns, err := csvReader.schema.columns()
+
+	if err != nil {
+		return nil, err
+	}
+
+	//Match the columns by header name
+	csvReader.mapping, err = newCsvMapping(header, columns)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.strictSchema && csvReader.mapping.drift != nil {
+		return nil, &SchemaDriftError{Drift: *csvReader.mapping.drift}
+	}
+
+	return csvReader, nil
+}
+
+/*
+CsvFileEvents - Stream the CSV export of query, rows are parsed as they are read from the response body
+The returned reader must be closed to release the connection. Schema drift is logged to the client's logger.
+*/
+func (c *Client) CsvFileEvents(ctx context.Context, authData AuthData, query Query, opts ...CsvReaderOption) (*CsvFileEventReader, error) {
+	resp, err := c.postQuery(ctx, c.csvExportURL(), authData, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := newCsvFileEventReader(ctx, resp.Body, resp.Body, opts)
+
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	if drift := reader.Drift(); drift != nil {
+		c.logf("Warning: CSV export columns changed, %s", drift)
+	}
+
+	return reader, nil
+}
+
+// StreamCsvFileEvents - Call fn for every event of the CSV export of query, returning an error from fn stops the export
+func (c *Client) StreamCsvFileEvents(ctx context.Context, authData AuthData, query Query, fn func(CsvFileEvent) error, opts ...CsvReaderOption) error {
+	events, err := c.CsvFileEvents(ctx, authData, query, opts...)
+
+	if err != nil {
+		return err
+	}
+
+	defer events.Close()
+
+	for events.Next() {
+		err = fn(events.Event())
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return events.Err()
+}
+
+/*
+Next - Parse the next row, returns false at the end of the export or on error
+Rows with bad values stop the reader, are skipped or are returned partially populated depending on the ParseMode.
+*/
+func (r *CsvFileEventReader) Next() bool {
+	for !r.done {
+		if len(r.rows) == 0 {
+			if r.readErr != nil {
+				r.done = true
+				r.event = CsvFileEvent{}
+
+				if r.readErr != io.EOF {
+					r.err = r.wrapErr(r.readErr)
+				}
+
+				return false
+			}
+
+			r.fill()
+			continue
+		}
+
+		row := r.rows[0]
+		r.rows[0] = convertedCsvRow{}
+		r.rows = r.rows[1:]
+
+		if len(row.parseErrors) == 0 {
+			r.event = *row.fileEvent
+			return true
+		}
+
+		switch {
+		case r.mode == ParseStrict:
+			r.done = true
+			r.rows = nil
+			r.event = CsvFileEvent{}
+			r.err = row.parseErrors[0]
+			return false
+		case r.mode == ParsePartial && row.fileEvent != nil:
+			r.parseErrors = append(r.parseErrors, row.parseErrors...)
+			r.event = *row.fileEvent
+			return true
+		default:
+			r.parseErrors = append(r.parseErrors, row.parseErrors...)
+		}
+	}
+
+	return false
+}
+
+// Event - The current event
+func (r *CsvFileEventReader) Event() CsvFileEvent {
+	return r.event
+}
+
+// Schema - The schema version the export was read with, nil for an empty export
+func (r *CsvFileEventReader) Schema() *CsvSchema {
+	if r.mapping == nil {
+		return nil
+	}
+
+	return r.schema
+}
+
+// Drift - How the export's columns differ from the schema's columns, nil if they match exactly
+func (r *CsvFileEventReader) Drift() *CsvSchemaDrift {
+	if r.mapping == nil {
+		return nil
+	}
+
+	return r.mapping.drift
+}
+
+// ParseErrors - Errors of the rows which were skipped or partially populated in the lenient parse modes
+func (r *CsvFileEventReader) ParseErrors() []*ParseError {
+	return r.parseErrors
+}
+
+// Err - The error which stopped reading, nil at the end of the export
+func (r *CsvFileEventReader) Err() error {
+	return r.err
+}
+
+// Close - Release the response body, if the reader was created by a Client
+func (r *CsvFileEventReader) Close() error {
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+
+	r.done = true
+
+	if r.closer == nil {
+		return nil
+	}
+
+	return r.closer.Close()
+}
+
+// wrapErr returns ctx.Err() for reads that failed because ctx was cancelled
+func (r *CsvFileEventReader) wrapErr(err error) error {
+	if r.ctx.Err() != nil && !errors.Is(err, r.ctx.Err()) {
+		return r.ctx.Err()
+	}
+
+	return err
+}
@@ -0,0 +1,112 @@
+package ffs
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// csvRow builds an export row with the given values, keyed by header name
+func csvRow(values map[string]string) []string {
+	row := make([]string, len(csvHeaders))
+
+	for i, header := range csvHeaders {
+		row[i] = values[header]
+	}
+
+	return row
+}
+
+// csvExport builds a BOM prefixed export body with csvHeaders and rows
+func csvExport(rows ...[]string) []byte {
+	var buf bytes.Buffer
+
+	buf.Write([]byte{0xEF, 0xBB, 0xBF})
+
+	w := csv.NewWriter(&buf)
+	_ = w.Write(csvHeaders)
+	_ = w.WriteAll(rows)
+
+	return buf.Bytes()
+}
+
+func TestCsvFileEventReaderStreams(t *testing.T) {
+	pr, pw := io.Pipe()
+
+	go func() {
+		_, _ = pw.Write(csvExport(csvRow(map[string]string{"Event ID": "1", "File size (bytes)": "42"})))
+		//The body arrives through a pipe in pieces, never as a whole
+		_, _ = pw.Write([]byte("2" + string(bytes.Repeat([]byte(","), len(csvHeaders)-1)) + "\n"))
+		_ = pw.Close()
+	}()
+
+	reader, err := NewCsvFileEventReader(pr)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+
+	for reader.Next() {
+		ids = append(ids, reader.Event().EventId)
+	}
+
+	if reader.Err() != nil {
+		t.Fatal(reader.Err())
+	}
+
+	if len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+		t.Errorf("unexpected events: %v", ids)
+	}
+}
+
+func TestClientStreamCsvFileEvents(t *testing.T) {
+	var rows [][]string
+
+	for i := 0; i < 100; i++ {
+		rows = append(rows, csvRow(map[string]string{"Event ID": strconv.Itoa(i), "Date Observed (UTC)": "2020-01-01T00:00:00.123Z"}))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fileevent/export" {
+			t.Error("unexpected path: " + r.URL.Path)
+		}
+
+		_, _ = w.Write(csvExport(rows...))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL+"/fileevent"))
+
+	n := 0
+
+	err := c.StreamCsvFileEvents(context.Background(), AuthData{AccessToken: "token"}, jsonQuery, func(event CsvFileEvent) error {
+		if event.EventId != strconv.Itoa(n) || event.EventTimestamp == nil {
+			t.Errorf("unexpected event %d: %+v", n, event)
+		}
+
+		n++
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != len(rows) {
+		t.Errorf("expected %d events, got %d", len(rows), n)
+	}
+
+	events, err := c.GetCsvFileEvents(AuthData{AccessToken: "token"}, jsonQuery)
+
+	if err != nil || len(*events) != len(rows) {
+		t.Errorf("expected GetCsvFileEvents to return %d events, got %v", len(rows), err)
+	}
+}
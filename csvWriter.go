@@ -0,0 +1,266 @@
+package ffs
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FFS CSV Writer
+
+// utf8BOM is the byte order mark Code42 starts its CSV exports with
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// csvWriterConfig holds the settings applied by CsvWriterOptions
+type csvWriterConfig struct {
+	extraHeaders []string
+	omitBOM      bool
+	schema       *CsvSchema
+}
+
+// CsvWriterOption - Functional option for NewCsvFileEventWriter
+type CsvWriterOption func(*csvWriterConfig)
+
+// WithExtraColumns - Append columns taken from CsvFileEvent.Extras after the known columns
+func WithExtraColumns(headers ...string) CsvWriterOption {
+	return func(cfg *csvWriterConfig) {
+		cfg.extraHeaders = append(cfg.extraHeaders, headers...)
+	}
+}
+
+// WithoutBOM - Do not start the file with the UTF-8 byte order mark Code42 exports begin with
+func WithoutBOM() CsvWriterOption {
+	return func(cfg *csvWriterConfig) {
+		cfg.omitBOM = true
+	}
+}
+
+// WithWriterSchema - Write the columns of schema instead of the CurrentCsvSchemaVersion schema
+func WithWriterSchema(schema *CsvSchema) CsvWriterOption {
+	return func(cfg *csvWriterConfig) {
+		cfg.schema = schema
+	}
+}
+
+/*
+CsvFileEventWriter - Writes file events in the layout of the Code42 console CSV export
+Columns follow the schema's header order and values use the multi-value, timestamp and IP formatting
+the CSV parser expects, so a written file reads back into the same CsvFileEvent values.
+Call Flush when done, the header is written even if no events are.
+*/
+type CsvFileEventWriter struct {
+	out           io.Writer
+	writer        *csv.Writer
+	cfg           csvWriterConfig
+	columns       []csvColumn
+	err           error
+	headerWritten bool
+	record        []string
+}
+
+// NewCsvFileEventWriter - Create a CsvFileEventWriter writing to w
+func NewCsvFileEventWriter(w io.Writer, opts ...CsvWriterOption) *CsvFileEventWriter {
+	var cfg csvWriterConfig
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.schema == nil {
+		cfg.schema = LookupCsvSchema(CurrentCsvSchemaVersion)
+	}
+
+	//An invalid schema is reported by the first Write or Flush
+	columns, err := cfg.schema.columns()
+
+	return &CsvFileEventWriter{
+		out:     w,
+		writer:  csv.NewWriter(w),
+		cfg:     cfg,
+		columns: columns,
+		err:     err,
+		record:  make([]string, len(columns)+len(cfg.extraHeaders)),
+	}
+}
+
+// writeHeader writes the BOM and header row once
+func (w *CsvFileEventWriter) writeHeader() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if w.headerWritten {
+		return nil
+	}
+
+	w.headerWritten = true
+
+	if !w.cfg.omitBOM {
+		_, err := w.out.Write(utf8BOM)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.writer.Write(append(append([]string(nil), w.cfg.schema.Headers...), w.cfg.extraHeaders...))
+}
+
+// Write - Write a single event
+func (w *CsvFileEventWriter) Write(fileEvent CsvFileEvent) error {
+	err := w.writeHeader()
+
+	if err != nil {
+		return err
+	}
+
+	for i, column := range w.columns {
+		//Columns without a field are written from Extras
+		if column.format == nil {
+			w.record[i] = fileEvent.Extras[column.header]
+			continue
+		}
+
+		w.record[i] = column.format(&fileEvent)
+	}
+
+	for i, header := range w.cfg.extraHeaders {
+		w.record[len(w.columns)+i] = fileEvent.Extras[header]
+	}
+
+	return w.writer.Write(w.record)
+}
+
+// WriteJson - Convert a JSON file event with CsvFileEventFromJson and write it
+func (w *CsvFileEventWriter) WriteJson(jsonFileEvent JsonFileEvent) error {
+	fileEvent, err := CsvFileEventFromJson(jsonFileEvent)
+
+	if err != nil {
+		return err
+	}
+
+	return w.Write(*fileEvent)
+}
+
+// Flush - Write any buffered data, including the header if nothing was written yet
+func (w *CsvFileEventWriter) Flush() error {
+	err := w.writeHeader()
+
+	if err != nil {
+		return err
+	}
+
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
+
+/*
+CsvFileEventFromJson - Convert a JSON file event into the CSV representation
+Fields which are lists in JSON but single columns in CSV are joined with commas, create and modify
+timestamps are truncated to the seconds the CSV export carries. FieldErrors have no CSV column and are dropped.
+An error is returned for a shared value which is not a boolean.
+*/
+func CsvFileEventFromJson(e JsonFileEvent) (*CsvFileEvent, error) {
+	fileEvent := CsvFileEvent{
+		EventId:                     e.EventId,
+		EventType:                   e.EventType,
+		FilePath:                    e.FilePath,
+		FileName:                    e.FileName,
+		FileType:                    e.FileType,
+		FileCategory:                e.FileCategory,
+		IdentifiedExtensionCategory: e.FileCategoryByBytes,
+		CurrentExtensionCategory:    e.FileCategoryByExtension,
+		FileSize:                    int64ToInt(e.FileSize),
+		Md5Checksum:                 e.Md5Checksum,
+		Sha256Checksum:              e.Sha256Checksum,
+		DeviceUsername:              e.DeviceUserName,
+		DeviceUid:                   e.DeviceUid,
+		UserUid:                     e.UserUid,
+		OsHostname:                  e.OsHostName,
+		DomainName:                  e.DomainName,
+		PublicIpAddress:             e.PublicIpAddress,
+		PrivateIpAddresses:          e.PrivateIpAddresses,
+		Actor:                       e.Actor,
+		DirectoryId:                 e.DirectoryId,
+		Source:                      e.Source,
+		Url:                         e.Url,
+		SharingTypeAdded:            e.SharingTypeAdded,
+		CloudDriveId:                e.CloudDriveId,
+		DetectionSourceAlias:        e.DetectionSourceAlias,
+		FileId:                      e.FileId,
+		Exposure:                    e.Exposure,
+		ProcessOwner:                e.ProcessOwner,
+		ProcessName:                 e.ProcessName,
+		TabWindowTitle:              strings.Join(e.WindowTitle, ","),
+		TabUrl:                      e.TabUrl,
+		RemovableMediaVendor:        e.RemovableMediaVendor,
+		RemovableMediaName:          e.RemovableMediaName,
+		RemovableMediaSerialNumber:  e.RemovableMediaSerialNumber,
+		RemovableMediaCapacity:      int64ToInt(e.RemovableMediaCapacity),
+		RemovableMediaBusType:       e.RemovableMediaBusType,
+		RemovableMediaMediaName:     e.RemovableMediaMediaName,
+		RemovableMediaVolumeName:    strings.Join(e.RemovableMediaVolumeName, ","),
+		RemovableMediaPartitionId:   strings.Join(e.RemovableMediaPartitionId, ","),
+		SyncDestination:             e.SyncDestination,
+		SyncDestinationUsername:     strings.Join(e.SyncDestinationUsername, ","),
+		EmailDLPPolicyNames:         e.EmailDlpPolicyNames,
+		EmailDLPSubject:             e.EmailSubject,
+		EmailDLPSender:              e.EmailSender,
+		EmailDLPFrom:                e.EmailFrom,
+		EmailDLPRecipients:          e.EmailRecipients,
+		OutsideActiveHours:          e.OutsideActiveHours,
+		IdentifiedExtensionMIMEType: e.MimeTypeByBytes,
+		CurrentExtensionMIMEType:    e.MimeTypeByExtension,
+		SuspiciousFileTypeMismatch:  e.MimeTypeMismatch,
+		PrintJobName:                e.PrintJobName,
+		PrinterName:                 e.PrinterName,
+		RemoteActivity:              e.RemoteActivity,
+		Trusted:                     e.Trusted,
+		LoggedInOperatingSystemUser: e.OperatingSystemUser,
+		DestinationCategory:         e.DestinationCategory,
+		DestinationName:             e.DestinationName,
+		EventTimestamp:              timestampTime(e.EventTimestamp, false),
+		InsertionTimestamp:          timestampTime(e.InsertionTimestamp, false),
+		CreatedTimestamp:            timestampTime(e.CreateTimestamp, true),
+		ModifyTimestamp:             timestampTime(e.ModifyTimestamp, true),
+	}
+
+	if e.FileOwner != "" {
+		fileEvent.FileOwner = strings.Split(e.FileOwner, ",")
+	}
+
+	if e.Shared != "" {
+		shared, err := strconv.ParseBool(e.Shared)
+
+		if err != nil {
+			return nil, err
+		}
+
+		fileEvent.Shared = &shared
+	}
+
+	for _, sharedWith := range e.SharedWith {
+		if sharedWith.CloudUsername != nil {
+			fileEvent.SharedWith = append(fileEvent.SharedWith, *sharedWith.CloudUsername)
+		}
+	}
+
+	for _, tab := range e.Tabs {
+		fileEvent.TabTitles = append(fileEvent.TabTitles, tab.Title)
+		fileEvent.TabURLs = append(fileEvent.TabURLs, tab.Url)
+	}
+
+	return &fileEvent, nil
+}
+
+func int64ToInt(value *int64) *int {
+	if value == nil {
+		return nil
+	}
+
+	converted := int(*value)
+
+	return &converted
+}
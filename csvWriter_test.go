@@ -0,0 +1,208 @@
+package ffs
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCsvFileEventWriterRoundTrip(t *testing.T) {
+	eventTimestamp := time.Date(2020, 3, 4, 5, 6, 7, 123000000, time.UTC)
+	modifyTimestamp := time.Date(2020, 3, 1, 2, 3, 4, 0, time.UTC)
+	size := 2048
+	shared := true
+
+	events := []CsvFileEvent{
+		{
+			EventId:            "a",
+			EventType:          "CREATED",
+			EventTimestamp:     &eventTimestamp,
+			ModifyTimestamp:    &modifyTimestamp,
+			FileName:           "report, final.xlsx",
+			FileSize:           &size,
+			FileOwner:          []string{"alice", "bob"},
+			PublicIpAddress:    "203.0.113.7",
+			PrivateIpAddresses: []string{"10.0.0.1", "fe80::1"},
+			Shared:             &shared,
+			TabTitles:          []string{"Inbox", "Drafts"},
+			TabURLs:            []string{"https://a", "https://b"},
+			Extras:             map[string]string{"Brand New Column": "surprise"},
+		},
+		{
+			EventId:   "b",
+			EventType: "DELETED",
+			FilePath:  "C:/Users/alice/\"quoted\"\nname",
+		},
+	}
+
+	var buf bytes.Buffer
+
+	writer := NewCsvFileEventWriter(&buf, WithExtraColumns("Brand New Column"))
+
+	for _, event := range events {
+		if err := writer.Write(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), utf8BOM) {
+		t.Error("export does not start with a byte order mark")
+	}
+
+	reader, err := NewCsvFileEventReader(&buf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if drift := reader.Drift(); drift == nil || len(drift.Added) != 1 || len(drift.Missing) != 0 || drift.Reordered {
+		t.Errorf("unexpected drift: %+v", drift)
+	}
+
+	var read []CsvFileEvent
+
+	for reader.Next() {
+		read = append(read, reader.Event())
+	}
+
+	if err := reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(read, events) {
+		t.Errorf("round trip changed the events:\n%+v\n%+v", read, events)
+	}
+}
+
+func TestCsvFileEventWriterHeaderOnly(t *testing.T) {
+	var buf bytes.Buffer
+
+	writer := NewCsvFileEventWriter(&buf, WithoutBOM())
+
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := NewCsvFileEventReader(&buf, WithStrictSchema())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reader.Next() {
+		t.Errorf("unexpected event: %+v", reader.Event())
+	}
+}
+
+func TestCsvFileEventWriterWriteJson(t *testing.T) {
+	size := int64(10)
+	username := "carol@example.com"
+
+	jsonEvent := JsonFileEvent{
+		EventId:                 "j",
+		EventTimestamp:          mustParseTimestamp(t, "2020-03-04T05:06:07.123Z"),
+		CreateTimestamp:         mustParseTimestamp(t, "2020-03-01T02:03:04.567Z"),
+		FileSize:                &size,
+		FileOwner:               "alice",
+		DeviceUserName:          "alice@example.com",
+		EmailRecipients:         []string{"x@example.com", "y@example.com"},
+		SyncDestinationUsername: []string{"alice", "alice2"},
+		Shared:                  "false",
+		SharedWith:              []SharedWith{{CloudUsername: &username}},
+		Tabs:                    []Tab{{Title: "Inbox", Url: "https://a"}},
+	}
+
+	var buf bytes.Buffer
+
+	writer := NewCsvFileEventWriter(&buf)
+
+	if err := writer.WriteJson(jsonEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := NewCsvFileEventReader(&buf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reader.Next() {
+		t.Fatal(reader.Err())
+	}
+
+	event := reader.Event()
+
+	if event.EventId != "j" || event.EventTimestamp == nil || event.EventTimestamp.Format(ffsTimestampFormat) != jsonEvent.EventTimestamp.String() {
+		t.Errorf("unexpected event timestamp: %+v", event)
+	}
+
+	if event.CreatedTimestamp == nil || !event.CreatedTimestamp.Equal(time.Date(2020, 3, 1, 2, 3, 4, 0, time.UTC)) {
+		t.Errorf("unexpected created timestamp: %v", event.CreatedTimestamp)
+	}
+
+	if event.FileSize == nil || *event.FileSize != 10 || event.DeviceUsername != "alice@example.com" || event.SyncDestinationUsername != "alice,alice2" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	if !reflect.DeepEqual(event.EmailDLPRecipients, jsonEvent.EmailRecipients) || !reflect.DeepEqual(event.SharedWith, []string{username}) || !reflect.DeepEqual(event.TabURLs, []string{"https://a"}) {
+		t.Errorf("unexpected list values: %+v", event)
+	}
+
+	if event.Shared == nil || *event.Shared {
+		t.Errorf("unexpected shared: %v", event.Shared)
+	}
+}
+
+func TestCsvFileEventWriterConvertsToUTC(t *testing.T) {
+	jsonEvent := JsonFileEvent{
+		EventId:         "z",
+		EventTimestamp:  mustParseTimestamp(t, "2020-01-01T00:00:00.250-05:00"),
+		CreateTimestamp: mustParseTimestamp(t, "2020-01-01T00:00:00-05:00"),
+	}
+
+	var buf bytes.Buffer
+
+	writer := NewCsvFileEventWriter(&buf)
+
+	if err := writer.WriteJson(jsonEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "-05:00") {
+		t.Errorf("times were written with their zone: %s", buf.String())
+	}
+
+	reader, err := NewCsvFileEventReader(&buf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reader.Next() {
+		t.Fatal(reader.Err())
+	}
+
+	event := reader.Event()
+
+	if event.EventTimestamp == nil || !event.EventTimestamp.Equal(jsonEvent.EventTimestamp.Time) {
+		t.Errorf("event timestamp %v, want %v", event.EventTimestamp, jsonEvent.EventTimestamp.Time)
+	}
+
+	if event.CreatedTimestamp == nil || !event.CreatedTimestamp.Equal(jsonEvent.CreateTimestamp.Time) {
+		t.Errorf("created timestamp %v, want %v", event.CreatedTimestamp, jsonEvent.CreateTimestamp.Time)
+	}
+}
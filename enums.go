@@ -0,0 +1,187 @@
+package ffs
+
+import (
+	"errors"
+	"strings"
+)
+
+// FFS Enumerated Values
+
+/*
+The types below name the documented values of file event fields. They are strings underneath,
+so values Code42 adds later still decode, IsKnown reports whether a value is one of the constants.
+Parse functions match case insensitively and return the constant's spelling.
+*/
+
+// EventType - Value of the eventType field
+type EventType string
+
+const (
+	EventTypeCreated   EventType = "CREATED"
+	EventTypeModified  EventType = "MODIFIED"
+	EventTypeDeleted   EventType = "DELETED"
+	EventTypeReadByApp EventType = "READ_BY_APP"
+	EventTypeEmailed   EventType = "EMAILED"
+)
+
+var eventTypes = []EventType{EventTypeCreated, EventTypeModified, EventTypeDeleted, EventTypeReadByApp, EventTypeEmailed}
+
+// Source - Value of the source field, where the event was observed
+type Source string
+
+const (
+	SourceEndpoint    Source = "Endpoint"
+	SourceGoogleDrive Source = "GoogleDrive"
+	SourceOneDrive    Source = "OneDrive"
+	SourceBox         Source = "Box"
+	SourceGmail       Source = "Gmail"
+	SourceOffice365   Source = "Office365"
+)
+
+var sources = []Source{SourceEndpoint, SourceGoogleDrive, SourceOneDrive, SourceBox, SourceGmail, SourceOffice365}
+
+// ExposureType - Value of the exposure field
+type ExposureType string
+
+const (
+	ExposureApplicationRead       ExposureType = "ApplicationRead"
+	ExposureCloudStorage          ExposureType = "CloudStorage"
+	ExposureIsPublic              ExposureType = "IsPublic"
+	ExposureOutsideTrustedDomains ExposureType = "OutsideTrustedDomains"
+	ExposureRemovableMedia        ExposureType = "RemovableMedia"
+	ExposureSharedToDomain        ExposureType = "SharedToDomain"
+	ExposureSharedViaLink         ExposureType = "SharedViaLink"
+)
+
+var exposureTypes = []ExposureType{ExposureApplicationRead, ExposureCloudStorage, ExposureIsPublic, ExposureOutsideTrustedDomains, ExposureRemovableMedia, ExposureSharedToDomain, ExposureSharedViaLink}
+
+// FileCategory - Value of the fileCategory, fileCategoryByBytes and fileCategoryByExtension fields
+type FileCategory string
+
+const (
+	FileCategoryAudio            FileCategory = "AUDIO"
+	FileCategoryDocument         FileCategory = "DOCUMENT"
+	FileCategoryExecutable       FileCategory = "EXECUTABLE"
+	FileCategoryImage            FileCategory = "IMAGE"
+	FileCategoryPdf              FileCategory = "PDF"
+	FileCategoryPresentation     FileCategory = "PRESENTATION"
+	FileCategoryScript           FileCategory = "SCRIPT"
+	FileCategorySourceCode       FileCategory = "SOURCE_CODE"
+	FileCategorySpreadsheet      FileCategory = "SPREADSHEET"
+	FileCategoryVideo            FileCategory = "VIDEO"
+	FileCategoryVirtualDiskImage FileCategory = "VIRTUAL_DISK_IMAGE"
+	FileCategoryZip              FileCategory = "ZIP"
+)
+
+var fileCategories = []FileCategory{FileCategoryAudio, FileCategoryDocument, FileCategoryExecutable, FileCategoryImage, FileCategoryPdf, FileCategoryPresentation, FileCategoryScript, FileCategorySourceCode, FileCategorySpreadsheet, FileCategoryVideo, FileCategoryVirtualDiskImage, FileCategoryZip}
+
+// DestinationCategory - Value of the destinationCategory field
+type DestinationCategory string
+
+const (
+	DestinationCategoryCloudStorage          DestinationCategory = "Cloud Storage"
+	DestinationCategoryDevice                DestinationCategory = "Device"
+	DestinationCategoryEmail                 DestinationCategory = "Email"
+	DestinationCategoryMessaging             DestinationCategory = "Messaging"
+	DestinationCategoryMultiplePossibilities DestinationCategory = "Multiple Possibilities"
+	DestinationCategorySocialMedia           DestinationCategory = "Social Media"
+	DestinationCategorySourceCodeRepository  DestinationCategory = "Source Code Repository"
+	DestinationCategoryUncategorized         DestinationCategory = "Uncategorized"
+	DestinationCategoryUnknown               DestinationCategory = "Unknown"
+)
+
+var destinationCategories = []DestinationCategory{DestinationCategoryCloudStorage, DestinationCategoryDevice, DestinationCategoryEmail, DestinationCategoryMessaging, DestinationCategoryMultiplePossibilities, DestinationCategorySocialMedia, DestinationCategorySourceCodeRepository, DestinationCategoryUncategorized, DestinationCategoryUnknown}
+
+// RemovableMediaBusType - Value of the removableMediaBusType field, the bus the removable media was attached to
+type RemovableMediaBusType string
+
+const (
+	RemovableMediaBusTypeUSB         RemovableMediaBusType = "USB"
+	RemovableMediaBusTypeATA         RemovableMediaBusType = "ATA"
+	RemovableMediaBusTypeATAPI       RemovableMediaBusType = "ATAPI"
+	RemovableMediaBusTypeSATA        RemovableMediaBusType = "SATA"
+	RemovableMediaBusTypeSCSI        RemovableMediaBusType = "SCSI"
+	RemovableMediaBusTypeSAS         RemovableMediaBusType = "SAS"
+	RemovableMediaBusTypeSD          RemovableMediaBusType = "SD"
+	RemovableMediaBusTypeMMC         RemovableMediaBusType = "MMC"
+	RemovableMediaBusTypeFireWire    RemovableMediaBusType = "1394"
+	RemovableMediaBusTypeThunderbolt RemovableMediaBusType = "Thunderbolt"
+	RemovableMediaBusTypeNVMe        RemovableMediaBusType = "NVMe"
+)
+
+var removableMediaBusTypes = []RemovableMediaBusType{RemovableMediaBusTypeUSB, RemovableMediaBusTypeATA, RemovableMediaBusTypeATAPI, RemovableMediaBusTypeSATA, RemovableMediaBusTypeSCSI, RemovableMediaBusTypeSAS, RemovableMediaBusTypeSD, RemovableMediaBusTypeMMC, RemovableMediaBusTypeFireWire, RemovableMediaBusTypeThunderbolt, RemovableMediaBusTypeNVMe}
+
+func (t EventType) String() string             { return string(t) }
+func (s Source) String() string                { return string(s) }
+func (e ExposureType) String() string          { return string(e) }
+func (c FileCategory) String() string          { return string(c) }
+func (c DestinationCategory) String() string   { return string(c) }
+func (b RemovableMediaBusType) String() string { return string(b) }
+
+// IsKnown - Whether t is one of the EventType constants
+func (t EventType) IsKnown() bool { return isKnown(t, eventTypes) }
+
+// IsKnown - Whether s is one of the Source constants
+func (s Source) IsKnown() bool { return isKnown(s, sources) }
+
+// IsKnown - Whether e is one of the ExposureType constants
+func (e ExposureType) IsKnown() bool { return isKnown(e, exposureTypes) }
+
+// IsKnown - Whether c is one of the FileCategory constants
+func (c FileCategory) IsKnown() bool { return isKnown(c, fileCategories) }
+
+// IsKnown - Whether c is one of the DestinationCategory constants
+func (c DestinationCategory) IsKnown() bool { return isKnown(c, destinationCategories) }
+
+// IsKnown - Whether b is one of the RemovableMediaBusType constants
+func (b RemovableMediaBusType) IsKnown() bool { return isKnown(b, removableMediaBusTypes) }
+
+// ParseEventType - The EventType constant matching value, or value itself and an error if it is unknown
+func ParseEventType(value string) (EventType, error) {
+	return parseEnum(value, eventTypes, "event type")
+}
+
+// ParseSource - The Source constant matching value, or value itself and an error if it is unknown
+func ParseSource(value string) (Source, error) {
+	return parseEnum(value, sources, "source")
+}
+
+// ParseExposureType - The ExposureType constant matching value, or value itself and an error if it is unknown
+func ParseExposureType(value string) (ExposureType, error) {
+	return parseEnum(value, exposureTypes, "exposure type")
+}
+
+// ParseFileCategory - The FileCategory constant matching value, or value itself and an error if it is unknown
+func ParseFileCategory(value string) (FileCategory, error) {
+	return parseEnum(value, fileCategories, "file category")
+}
+
+// ParseDestinationCategory - The DestinationCategory constant matching value, or value itself and an error if it is unknown
+func ParseDestinationCategory(value string) (DestinationCategory, error) {
+	return parseEnum(value, destinationCategories, "destination category")
+}
+
+// ParseRemovableMediaBusType - The RemovableMediaBusType constant matching value, or value itself and an error if it is unknown
+func ParseRemovableMediaBusType(value string) (RemovableMediaBusType, error) {
+	return parseEnum(value, removableMediaBusTypes, "removable media bus type")
+}
+
+func isKnown[T ~string](value T, known []T) bool {
+	for _, k := range known {
+		if value == k {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseEnum[T ~string](value string, known []T, name string) (T, error) {
+	for _, k := range known {
+		if strings.EqualFold(value, string(k)) {
+			return k, nil
+		}
+	}
+
+	return T(value), errors.New("unknown " + name + ": " + value)
+}
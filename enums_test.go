@@ -0,0 +1,62 @@
+package ffs
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseEnums(t *testing.T) {
+	eventType, err := ParseEventType("read_by_app")
+
+	if err != nil || eventType != EventTypeReadByApp || !eventType.IsKnown() {
+		t.Errorf("unexpected event type: %s %v", eventType, err)
+	}
+
+	category, err := ParseDestinationCategory("cloud storage")
+
+	if err != nil || category != DestinationCategoryCloudStorage {
+		t.Errorf("unexpected destination category: %s %v", category, err)
+	}
+
+	busType, err := ParseRemovableMediaBusType("Floppy")
+
+	if err == nil || busType != "Floppy" || busType.IsKnown() {
+		t.Errorf("expected an unknown bus type, got %s %v", busType, err)
+	}
+}
+
+func TestEnumsAcceptUnknownValues(t *testing.T) {
+	var event JsonFileEvent
+
+	err := json.Unmarshal([]byte(`{"eventType":"PRINTED","source":"Endpoint","exposure":["RemovableMedia","Teleported"],"fileCategory":"SPREADSHEET"}`), &event)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.EventType != "PRINTED" || event.EventType.IsKnown() {
+		t.Errorf("unexpected event type: %s", event.EventType)
+	}
+
+	if event.Source != SourceEndpoint || event.FileCategory != FileCategorySpreadsheet {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	if len(event.Exposure) != 2 || event.Exposure[0] != ExposureRemovableMedia || event.Exposure[1].IsKnown() {
+		t.Errorf("unexpected exposure: %v", event.Exposure)
+	}
+
+	reader, err := NewCsvFileEventReader(csvBody([]string{"Event type", "Exposure Type"}, []string{"CREATED", "IsPublic,Teleported"}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reader.Next() {
+		t.Fatal(reader.Err())
+	}
+
+	if csvEvent := reader.Event(); csvEvent.EventType != EventTypeCreated || len(csvEvent.Exposure) != 2 || csvEvent.Exposure[0] != ExposureIsPublic {
+		t.Errorf("unexpected csv event: %+v", csvEvent)
+	}
+}
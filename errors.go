@@ -0,0 +1,90 @@
+package ffs
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// FFS Errors
+
+// Sentinel errors, match them with errors.Is
+var (
+	// ErrMaintenance - The Code42 API reported "Service Under Maintenance"
+	ErrMaintenance = errors.New("api service is under maintenance")
+	// ErrUnauthorized - The request was rejected with 401 Unauthorized, the token is missing, expired or revoked
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited - The request was rejected with 429 Too Many Requests
+	ErrRateLimited = errors.New("rate limited")
+	// ErrTooManyFilters - The query holds more filters than the client allows per query and was not sent
+	ErrTooManyFilters = errors.New("too many filters")
+)
+
+// maxErrorBodySize - Number of response body bytes kept in an HTTPError
+const maxErrorBodySize = 4096
+
+// maintenanceMarker is the text Code42 serves while its API is under maintenance
+const maintenanceMarker = "Service Under Maintenance"
+
+/*
+HTTPError - Returned when a Code42 endpoint answers with an unexpected status code
+Body holds at most the first 4096 bytes of the response body.
+errors.Is matches ErrUnauthorized for 401, ErrRateLimited for 429 and ErrMaintenance for maintenance pages.
+*/
+type HTTPError struct {
+	Op         string
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       string
+}
+
+// newHTTPError builds an HTTPError for resp, reading a truncated copy of its body
+func newHTTPError(op string, resp *http.Response) *HTTPError {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	return &HTTPError{
+		Op:         op,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		Body:       string(body),
+	}
+}
+
+func (e *HTTPError) Error() string {
+	return "Error with " + e.Op + ": " + e.Status
+}
+
+// Is - Match the sentinel error corresponding to the status code
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrMaintenance:
+		return strings.Contains(e.Body, maintenanceMarker)
+	}
+
+	return false
+}
+
+// QueryProblemsError - Returned when FFS rejects a query and responds with problems instead of file events
+type QueryProblemsError struct {
+	Problems []QueryProblem
+}
+
+// Error - The problems as JSON, matching the message returned before this type existed
+func (e *QueryProblemsError) Error() string {
+	problems, err := json.Marshal(e.Problems)
+
+	if err != nil {
+		return "query problems: " + err.Error()
+	}
+
+	return string(problems)
+}
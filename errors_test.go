@@ -0,0 +1,73 @@
+package ffs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPErrorIs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(strings.Repeat("x", maxErrorBodySize*2)))
+	}))
+	defer server.Close()
+
+	_, _, err := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL), WithRetryPolicy(NoRetry)).GetJsonFileEvents(AuthData{AccessToken: "token"}, jsonQuery, "")
+
+	if !errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected only ErrRateLimited to match, got %v", err)
+	}
+
+	var httpErr *HTTPError
+
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected an *HTTPError, got %T", err)
+	}
+
+	if httpErr.StatusCode != http.StatusTooManyRequests || httpErr.Header.Get("Retry-After") != "30" || len(httpErr.Body) != maxErrorBodySize {
+		t.Errorf("unexpected HTTPError: %d %q %d", httpErr.StatusCode, httpErr.Header.Get("Retry-After"), len(httpErr.Body))
+	}
+
+	if httpErr.Error() != "Error with gathering file events POST: 429 Too Many Requests" {
+		t.Error("unexpected message: " + httpErr.Error())
+	}
+}
+
+func TestAuthMaintenanceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html>Service Under Maintenance</html>"))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(WithAuthURL(server.URL), WithRetryPolicy(NoRetry)).GetAuthData("user", "pass")
+
+	if !errors.Is(err, ErrMaintenance) {
+		t.Errorf("expected ErrMaintenance, got %v", err)
+	}
+}
+
+func TestQueryProblemsError(t *testing.T) {
+	problem := QueryProblem{BadFilter: SearchFilter{Operator: "IS", Term: "bogus", Value: "x"}, Type: "SEARCH_FAILED", Description: "unknown term"}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(JsonFileEventResponse{Problems: []QueryProblem{problem}})
+	}))
+	defer server.Close()
+
+	_, _, err := GetJsonFileEvents(AuthData{AccessToken: "token"}, server.URL, jsonQuery, "", false)
+
+	var problemsErr *QueryProblemsError
+
+	if !errors.As(err, &problemsErr) {
+		t.Fatalf("expected a *QueryProblemsError, got %T", err)
+	}
+
+	if len(problemsErr.Problems) != 1 || problemsErr.Problems[0] != problem {
+		t.Errorf("unexpected problems: %+v", problemsErr.Problems)
+	}
+}
@@ -0,0 +1,44 @@
+package ffs
+
+import (
+	"os"
+	"testing"
+)
+
+// Integration test settings, read from the environment so credentials never live in the repo
+var (
+	authUri  = os.Getenv("FFS_AUTH_URI")
+	username = os.Getenv("FFS_USERNAME")
+	password = os.Getenv("FFS_PASSWORD")
+	ffsUri   = os.Getenv("FFS_URI")
+)
+
+// Returns all events within a 5 second delta
+var jsonQuery = Query{
+	Groups: []Group{
+		{
+			Filters: []SearchFilter{
+				{Operator: "IS", Term: "fileName", Value: "*"},
+				{Operator: "ON_OR_AFTER", Term: "insertionTimestamp", Value: "2019-08-18T20:31:48.728Z"},
+				{Operator: "ON_OR_BEFORE", Term: "insertionTimestamp", Value: "2019-08-18T20:32:03.728Z"},
+			},
+			FilterClause: "AND",
+		},
+	},
+	GroupClause: "AND",
+	PgSize:      100,
+	SrtDir:      "asc",
+	SrtKey:      "insertionTimestamp",
+}
+
+// skipWithoutCredentials skips tests which need a live Code42 tenant
+func skipWithoutCredentials(t *testing.T) {
+	if authUri == "" || username == "" || password == "" {
+		t.Skip("FFS_AUTH_URI, FFS_USERNAME and FFS_PASSWORD must be set to run integration tests")
+	}
+}
+
+func init() {
+	//Unit tests talk to local test servers, keep the shared limiter from slowing them down
+	DefaultRateLimiter = nil
+}
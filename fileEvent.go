@@ -0,0 +1,300 @@
+package ffs
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FFS File Event
+
+/*
+FileEvent - A file event independent of the export it came from
+Fields use the JSON API names with the richest type either export provides. Use FileEventFromJson and
+FileEventFromCsv to convert, no value of either representation is dropped. Values the CSV export keeps as
+a single column but the JSON API as a list (WindowTitle, RemovableMediaVolumeName, RemovableMediaPartitionId,
+SyncDestinationUsername) are not split, they become a single element.
+*/
+type FileEvent struct {
+	Actor                      string                     `json:"actor,omitempty"`
+	CloudDriveId               string                     `json:"cloudDriveId,omitempty"`
+	CreateTimestamp            *time.Time                 `json:"createTimestamp,omitempty"`
+	DestinationCategory        DestinationCategory        `json:"destinationCategory,omitempty"`
+	DestinationName            string                     `json:"destinationName,omitempty"`
+	DetectionSourceAlias       string                     `json:"detectionSourceAlias,omitempty"`
+	DeviceUid                  string                     `json:"deviceUid,omitempty"`
+	DeviceUserName             string                     `json:"deviceUserName,omitempty"`
+	DirectoryId                []string                   `json:"directoryId,omitempty"`
+	DomainName                 string                     `json:"domainName,omitempty"`
+	EmailDlpPolicyNames        []string                   `json:"emailDlpPolicyNames,omitempty"`
+	EmailFrom                  string                     `json:"emailFrom,omitempty"`
+	EmailRecipients            []string                   `json:"emailRecipients,omitempty"`
+	EmailSender                string                     `json:"emailSender,omitempty"`
+	EmailSubject               string                     `json:"emailSubject,omitempty"`
+	EventId                    string                     `json:"eventId"`
+	EventTimestamp             *time.Time                 `json:"eventTimestamp,omitempty"`
+	EventType                  EventType                  `json:"eventType,omitempty"`
+	Exposure                   []ExposureType             `json:"exposure,omitempty"`
+	FieldErrors                []FieldError               `json:"fieldErrors,omitempty"`
+	FileCategory               FileCategory               `json:"fileCategory,omitempty"`
+	FileCategoryByBytes        FileCategory               `json:"fileCategoryByBytes,omitempty"`
+	FileCategoryByExtension    FileCategory               `json:"fileCategoryByExtension,omitempty"`
+	FileId                     string                     `json:"fileId,omitempty"`
+	FileName                   string                     `json:"fileName,omitempty"`
+	FileOwner                  []string                   `json:"fileOwner,omitempty"`
+	FilePath                   string                     `json:"filePath,omitempty"`
+	FileSize                   *int64                     `json:"fileSize,omitempty"`
+	FileType                   string                     `json:"fileType,omitempty"`
+	InsertionTimestamp         *time.Time                 `json:"insertionTimestamp,omitempty"`
+	Md5Checksum                string                     `json:"md5Checksum,omitempty"`
+	MimeTypeByBytes            string                     `json:"mimeTypeByBytes,omitempty"`
+	MimeTypeByExtension        string                     `json:"mimeTypeByExtension,omitempty"`
+	MimeTypeMismatch           *bool                      `json:"mimeTypeMismatch,omitempty"`
+	ModifyTimestamp            *time.Time                 `json:"modifyTimestamp,omitempty"`
+	OperatingSystemUser        string                     `json:"operatingSystemUser,omitempty"`
+	OsHostName                 string                     `json:"osHostName,omitempty"`
+	OutsideActiveHours         *bool                      `json:"outsideActiveHours,omitempty"`
+	PrintJobName               string                     `json:"printJobName,omitempty"`
+	PrintedFilesBackupPath     string                     `json:"printedFilesBackupPath,omitempty"`
+	PrinterName                string                     `json:"printerName,omitempty"`
+	PrivateIpAddresses         []string                   `json:"privateIpAddresses,omitempty"`
+	ProcessName                string                     `json:"processName,omitempty"`
+	ProcessOwner               string                     `json:"processOwner,omitempty"`
+	PublicIpAddress            string                     `json:"publicIpAddress,omitempty"`
+	RemoteActivity             string                     `json:"remoteActivity,omitempty"`
+	RemovableMediaBusType      RemovableMediaBusType      `json:"removableMediaBusType,omitempty"`
+	RemovableMediaCapacity     *int64                     `json:"removableMediaCapacity,omitempty"`
+	RemovableMediaMediaName    string                     `json:"removableMediaMediaName,omitempty"`
+	RemovableMediaName         string                     `json:"removableMediaName,omitempty"`
+	RemovableMediaPartitionId  []string                   `json:"removableMediaPartitionId,omitempty"`
+	RemovableMediaSerialNumber string                     `json:"removableMediaSerialNumber,omitempty"`
+	RemovableMediaVendor       string                     `json:"removableMediaVendor,omitempty"`
+	RemovableMediaVolumeName   []string                   `json:"removableMediaVolumeName,omitempty"`
+	Sha256Checksum             string                     `json:"sha256Checksum,omitempty"`
+	Shared                     *bool                      `json:"shared,omitempty"`
+	SharedWith                 []SharedWith               `json:"sharedWith,omitempty"`
+	SharingTypeAdded           []string                   `json:"sharingTypeAdded,omitempty"`
+	Source                     Source                     `json:"source,omitempty"`
+	SyncDestination            string                     `json:"syncDestination,omitempty"`
+	SyncDestinationUsername    []string                   `json:"syncDestinationUsername,omitempty"`
+	TabUrl                     string                     `json:"tabUrl,omitempty"`
+	Tabs                       []Tab                      `json:"tabs,omitempty"`
+	Trusted                    *bool                      `json:"trusted,omitempty"`
+	Url                        string                     `json:"url,omitempty"`
+	UserUid                    string                     `json:"userUid,omitempty"`
+	WindowTitle                []string                   `json:"windowTitle,omitempty"`
+	Extras                     map[string]string          `json:"extras,omitempty"`     //Values of CSV columns this package does not know, keyed by header
+	JsonExtras                 map[string]json.RawMessage `json:"jsonExtras,omitempty"` //JSON properties this package does not know, see JsonFileEvent.Extras
+}
+
+/*
+FileEventFromJson - Convert a JSON file event into a FileEvent
+An error is returned for a shared value which is not a boolean.
+*/
+func FileEventFromJson(e JsonFileEvent) (*FileEvent, error) {
+	fileEvent := FileEvent{
+		Actor:                      e.Actor,
+		CloudDriveId:               e.CloudDriveId,
+		DestinationCategory:        e.DestinationCategory,
+		DestinationName:            e.DestinationName,
+		DetectionSourceAlias:       e.DetectionSourceAlias,
+		DeviceUid:                  e.DeviceUid,
+		DeviceUserName:             e.DeviceUserName,
+		DirectoryId:                e.DirectoryId,
+		DomainName:                 e.DomainName,
+		EmailDlpPolicyNames:        e.EmailDlpPolicyNames,
+		EmailFrom:                  e.EmailFrom,
+		EmailRecipients:            e.EmailRecipients,
+		EmailSender:                e.EmailSender,
+		EmailSubject:               e.EmailSubject,
+		EventId:                    e.EventId,
+		EventType:                  e.EventType,
+		Exposure:                   e.Exposure,
+		FieldErrors:                e.FieldErrors,
+		FileCategory:               e.FileCategory,
+		FileCategoryByBytes:        e.FileCategoryByBytes,
+		FileCategoryByExtension:    e.FileCategoryByExtension,
+		FileId:                     e.FileId,
+		FileName:                   e.FileName,
+		FilePath:                   e.FilePath,
+		FileSize:                   e.FileSize,
+		FileType:                   e.FileType,
+		Md5Checksum:                e.Md5Checksum,
+		MimeTypeByBytes:            e.MimeTypeByBytes,
+		MimeTypeByExtension:        e.MimeTypeByExtension,
+		MimeTypeMismatch:           e.MimeTypeMismatch,
+		OperatingSystemUser:        e.OperatingSystemUser,
+		OsHostName:                 e.OsHostName,
+		OutsideActiveHours:         e.OutsideActiveHours,
+		PrintJobName:               e.PrintJobName,
+		PrinterName:                e.PrinterName,
+		PrivateIpAddresses:         e.PrivateIpAddresses,
+		ProcessName:                e.ProcessName,
+		ProcessOwner:               e.ProcessOwner,
+		PublicIpAddress:            e.PublicIpAddress,
+		RemoteActivity:             e.RemoteActivity,
+		RemovableMediaBusType:      e.RemovableMediaBusType,
+		RemovableMediaCapacity:     e.RemovableMediaCapacity,
+		RemovableMediaMediaName:    e.RemovableMediaMediaName,
+		RemovableMediaName:         e.RemovableMediaName,
+		RemovableMediaPartitionId:  e.RemovableMediaPartitionId,
+		RemovableMediaSerialNumber: e.RemovableMediaSerialNumber,
+		RemovableMediaVendor:       e.RemovableMediaVendor,
+		RemovableMediaVolumeName:   e.RemovableMediaVolumeName,
+		Sha256Checksum:             e.Sha256Checksum,
+		SharedWith:                 e.SharedWith,
+		SharingTypeAdded:           e.SharingTypeAdded,
+		Source:                     e.Source,
+		SyncDestination:            e.SyncDestination,
+		SyncDestinationUsername:    e.SyncDestinationUsername,
+		TabUrl:                     e.TabUrl,
+		Tabs:                       e.Tabs,
+		Trusted:                    e.Trusted,
+		Url:                        e.Url,
+		UserUid:                    e.UserUid,
+		WindowTitle:                e.WindowTitle,
+		CreateTimestamp:            timestampTime(e.CreateTimestamp, false),
+		EventTimestamp:             timestampTime(e.EventTimestamp, false),
+		InsertionTimestamp:         timestampTime(e.InsertionTimestamp, false),
+		ModifyTimestamp:            timestampTime(e.ModifyTimestamp, false),
+	}
+
+	if e.Extras != nil {
+		fileEvent.JsonExtras = make(map[string]json.RawMessage, len(e.Extras))
+
+		for name, value := range e.Extras {
+			fileEvent.JsonExtras[name] = value
+		}
+	}
+
+	//Split like the CSV export's File Owner column
+	if e.FileOwner != "" {
+		fileEvent.FileOwner = strings.Split(e.FileOwner, ",")
+	}
+
+	if e.Shared != "" {
+		shared, err := strconv.ParseBool(e.Shared)
+
+		if err != nil {
+			return nil, err
+		}
+
+		fileEvent.Shared = &shared
+	}
+
+	return &fileEvent, nil
+}
+
+/*
+FileEventFromCsv - Convert a CSV file event into a FileEvent
+Tab titles and URLs are paired by position into Tabs, a list shorter than the other leaves its part of the extra tabs empty.
+*/
+func FileEventFromCsv(e CsvFileEvent) *FileEvent {
+	fileEvent := FileEvent{
+		Actor:                      e.Actor,
+		CloudDriveId:               e.CloudDriveId,
+		CreateTimestamp:            e.CreatedTimestamp,
+		DestinationCategory:        e.DestinationCategory,
+		DestinationName:            e.DestinationName,
+		DetectionSourceAlias:       e.DetectionSourceAlias,
+		DeviceUid:                  e.DeviceUid,
+		DeviceUserName:             e.DeviceUsername,
+		DirectoryId:                e.DirectoryId,
+		DomainName:                 e.DomainName,
+		EmailDlpPolicyNames:        e.EmailDLPPolicyNames,
+		EmailFrom:                  e.EmailDLPFrom,
+		EmailRecipients:            e.EmailDLPRecipients,
+		EmailSender:                e.EmailDLPSender,
+		EmailSubject:               e.EmailDLPSubject,
+		EventId:                    e.EventId,
+		EventTimestamp:             e.EventTimestamp,
+		EventType:                  e.EventType,
+		Exposure:                   e.Exposure,
+		FileCategory:               e.FileCategory,
+		FileCategoryByBytes:        e.IdentifiedExtensionCategory,
+		FileCategoryByExtension:    e.CurrentExtensionCategory,
+		FileId:                     e.FileId,
+		FileName:                   e.FileName,
+		FileOwner:                  e.FileOwner,
+		FilePath:                   e.FilePath,
+		FileSize:                   intToInt64(e.FileSize),
+		FileType:                   e.FileType,
+		InsertionTimestamp:         e.InsertionTimestamp,
+		Md5Checksum:                e.Md5Checksum,
+		MimeTypeByBytes:            e.IdentifiedExtensionMIMEType,
+		MimeTypeByExtension:        e.CurrentExtensionMIMEType,
+		MimeTypeMismatch:           e.SuspiciousFileTypeMismatch,
+		ModifyTimestamp:            e.ModifyTimestamp,
+		OperatingSystemUser:        e.LoggedInOperatingSystemUser,
+		OsHostName:                 e.OsHostname,
+		OutsideActiveHours:         e.OutsideActiveHours,
+		PrintJobName:               e.PrintJobName,
+		PrintedFilesBackupPath:     e.PrintedFilesBackupPath,
+		PrinterName:                e.PrinterName,
+		PrivateIpAddresses:         e.PrivateIpAddresses,
+		ProcessName:                e.ProcessName,
+		ProcessOwner:               e.ProcessOwner,
+		PublicIpAddress:            e.PublicIpAddress,
+		RemoteActivity:             e.RemoteActivity,
+		RemovableMediaBusType:      e.RemovableMediaBusType,
+		RemovableMediaCapacity:     intToInt64(e.RemovableMediaCapacity),
+		RemovableMediaMediaName:    e.RemovableMediaMediaName,
+		RemovableMediaName:         e.RemovableMediaName,
+		RemovableMediaPartitionId:  singleValue(e.RemovableMediaPartitionId),
+		RemovableMediaSerialNumber: e.RemovableMediaSerialNumber,
+		RemovableMediaVendor:       e.RemovableMediaVendor,
+		RemovableMediaVolumeName:   singleValue(e.RemovableMediaVolumeName),
+		Sha256Checksum:             e.Sha256Checksum,
+		Shared:                     e.Shared,
+		SharingTypeAdded:           e.SharingTypeAdded,
+		Source:                     e.Source,
+		SyncDestination:            e.SyncDestination,
+		SyncDestinationUsername:    singleValue(e.SyncDestinationUsername),
+		TabUrl:                     e.TabUrl,
+		Trusted:                    e.Trusted,
+		Url:                        e.Url,
+		UserUid:                    e.UserUid,
+		WindowTitle:                singleValue(e.TabWindowTitle),
+		Extras:                     e.Extras,
+	}
+
+	for i := range e.SharedWith {
+		fileEvent.SharedWith = append(fileEvent.SharedWith, SharedWith{CloudUsername: &e.SharedWith[i]})
+	}
+
+	for i := 0; i < len(e.TabTitles) || i < len(e.TabURLs); i++ {
+		var tab Tab
+
+		if i < len(e.TabTitles) {
+			tab.Title = e.TabTitles[i]
+		}
+
+		if i < len(e.TabURLs) {
+			tab.Url = e.TabURLs[i]
+		}
+
+		fileEvent.Tabs = append(fileEvent.Tabs, tab)
+	}
+
+	return &fileEvent
+}
+
+// singleValue returns value as a one element list, nil if it is empty
+func singleValue(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return []string{value}
+}
+
+func intToInt64(value *int) *int64 {
+	if value == nil {
+		return nil
+	}
+
+	converted := int64(*value)
+
+	return &converted
+}
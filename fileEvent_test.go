@@ -0,0 +1,106 @@
+package ffs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFileEventFromJsonAndCsvAgree(t *testing.T) {
+	size := int64(2048)
+	trusted := true
+	username := "carol@example.com"
+
+	jsonEvent := JsonFileEvent{
+		EventId:                 "a",
+		EventType:               "MODIFIED",
+		EventTimestamp:          mustParseTimestamp(t, "2020-03-04T05:06:07.123Z"),
+		InsertionTimestamp:      mustParseTimestamp(t, "2020-03-04T05:16:07.456Z"),
+		CreateTimestamp:         mustParseTimestamp(t, "2020-03-01T02:03:04Z"),
+		FileName:                "report.xlsx",
+		FileSize:                &size,
+		FileOwner:               "alice,bob",
+		DeviceUserName:          "alice@example.com",
+		OsHostName:              "laptop",
+		EmailFrom:               "alice@example.com",
+		EmailRecipients:         []string{"x@example.com", "y@example.com"},
+		MimeTypeByBytes:         "application/zip",
+		FileCategoryByExtension: "SPREADSHEET",
+		OperatingSystemUser:     "alice",
+		Shared:                  "true",
+		SharedWith:              []SharedWith{{CloudUsername: &username}},
+		Tabs:                    []Tab{{Title: "Inbox", Url: "https://a"}, {Title: "Drafts", Url: "https://b"}},
+		WindowTitle:             []string{"Inbox - Mail"},
+		SyncDestinationUsername: []string{"alice"},
+		Trusted:                 &trusted,
+	}
+
+	fromJson, err := FileEventFromJson(jsonEvent)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	csvEvent, err := CsvFileEventFromJson(jsonEvent)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromCsv := FileEventFromCsv(*csvEvent)
+
+	if !reflect.DeepEqual(fromJson, fromCsv) {
+		t.Errorf("conversions disagree:\n%+v\n%+v", fromJson, fromCsv)
+	}
+
+	if fromJson.EventTimestamp.Nanosecond() != 123000000 || len(fromJson.FileOwner) != 2 || fromJson.Shared == nil || !*fromJson.Shared {
+		t.Errorf("unexpected event: %+v", fromJson)
+	}
+}
+
+func TestFileEventKeepsRepresentationSpecificValues(t *testing.T) {
+	var jsonEvent JsonFileEvent
+
+	err := json.Unmarshal([]byte(`{"eventId":"a","fieldErrors":[{"field":"fileSize","error":"missing"}],"riskScore":9}`), &jsonEvent)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromJson, err := FileEventFromJson(jsonEvent)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fromJson.FieldErrors) != 1 || string(fromJson.JsonExtras["riskScore"]) != "9" {
+		t.Errorf("json only values dropped: %+v", fromJson)
+	}
+
+	fromCsv := FileEventFromCsv(CsvFileEvent{
+		EventId:                "b",
+		PrintedFilesBackupPath: "/backup/b.pdf",
+		TabTitles:              []string{"Inbox"},
+		TabURLs:                []string{"https://a", "https://b"},
+		TabWindowTitle:         "Report, final",
+		Extras:                 map[string]string{"Brand New Column": "surprise"},
+	})
+
+	if fromCsv.PrintedFilesBackupPath != "/backup/b.pdf" || fromCsv.Extras["Brand New Column"] != "surprise" {
+		t.Errorf("csv only values dropped: %+v", fromCsv)
+	}
+
+	if !reflect.DeepEqual(fromCsv.Tabs, []Tab{{Title: "Inbox", Url: "https://a"}, {Url: "https://b"}}) {
+		t.Errorf("unexpected tabs: %+v", fromCsv.Tabs)
+	}
+
+	if !reflect.DeepEqual(fromCsv.WindowTitle, []string{"Report, final"}) {
+		t.Errorf("window title was split: %v", fromCsv.WindowTitle)
+	}
+}
+
+func TestFileEventFromJsonSharedError(t *testing.T) {
+	if _, err := FileEventFromJson(JsonFileEvent{Shared: "maybe"}); err == nil {
+		t.Error("expected an error for a bad shared value")
+	}
+}
@@ -2,4 +2,7 @@ module github.com/BenB196/crashplan-ffs-go-pkg
 
 go 1.18
 
-require github.com/spkg/bom v1.0.0
+require (
+	github.com/spkg/bom v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
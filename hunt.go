@@ -0,0 +1,406 @@
+package ffs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FFS Saved Hunts
+
+// Hunt output formats
+const (
+	HuntFormatJson = "json"
+	HuntFormatCsv  = "csv"
+)
+
+/*
+Hunt - A saved query loaded from a YAML or JSON hunt file
+
+	name: spreadsheets-to-usb
+	description: Spreadsheets copied to removable media
+	groups:
+	  - filters:
+	      - term: fileName
+	        operator: IS_ANY
+	        values: ["*.xlsx", "*.csv"]
+	  - filters:
+	      - term: exposure
+	        operator: IS
+	        value: RemovableMedia
+	window:
+	  last: 7d
+	sort:
+	  term: insertionTimestamp
+	  direction: desc
+	output:
+	  format: csv
+	  path: spreadsheets.csv
+
+The filters are given either as groups or as query, a text query in the query language (see ParseQuery).
+GroupClause only applies to groups, a query joins its groups itself.
+*/
+type Hunt struct {
+	Name        string      `json:"name" yaml:"name"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Query       string      `json:"query,omitempty" yaml:"query,omitempty"`
+	Groups      []HuntGroup `json:"groups,omitempty" yaml:"groups,omitempty"`
+	GroupClause string      `json:"groupClause,omitempty" yaml:"groupClause,omitempty"`
+	Window      *HuntWindow `json:"window,omitempty" yaml:"window,omitempty"`
+	Sort        *HuntSort   `json:"sort,omitempty" yaml:"sort,omitempty"`
+	Output      HuntOutput  `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// HuntGroup - Filters joined with Clause, AND if empty
+type HuntGroup struct {
+	Clause  string       `json:"clause,omitempty" yaml:"clause,omitempty"`
+	Filters []HuntFilter `json:"filters" yaml:"filters"`
+}
+
+// HuntFilter - A filter of a hunt, IS_ANY and NOT_IN take Values, EXISTS and DOES_NOT_EXIST nothing, every other operator Value
+type HuntFilter struct {
+	Term     string   `json:"term" yaml:"term"`
+	Operator string   `json:"operator" yaml:"operator"`
+	Value    string   `json:"value,omitempty" yaml:"value,omitempty"`
+	Values   []string `json:"values,omitempty" yaml:"values,omitempty"`
+}
+
+/*
+HuntWindow - Time range of a hunt, resolved when the query is built
+Start and End are "now", "now-<duration>" or a timestamp, Last is a duration and short for Start now-<Last>.
+Durations are Go durations such as 36h or whole days and weeks such as 7d or 2w. End defaults to now.
+*/
+type HuntWindow struct {
+	//Term is the timestamp term of the range, defaults to insertionTimestamp
+	Term  string `json:"term,omitempty" yaml:"term,omitempty"`
+	Last  string `json:"last,omitempty" yaml:"last,omitempty"`
+	Start string `json:"start,omitempty" yaml:"start,omitempty"`
+	End   string `json:"end,omitempty" yaml:"end,omitempty"`
+}
+
+// HuntSort - Sort order of the results of a hunt, Direction defaults to asc
+type HuntSort struct {
+	Term      string `json:"term" yaml:"term"`
+	Direction string `json:"direction,omitempty" yaml:"direction,omitempty"`
+}
+
+// HuntOutput - Where tools running a hunt write its results
+type HuntOutput struct {
+	//Format is HuntFormatJson or HuntFormatCsv
+	Format   string `json:"format,omitempty" yaml:"format,omitempty"`
+	Path     string `json:"path,omitempty" yaml:"path,omitempty"`
+	PageSize int    `json:"pageSize,omitempty" yaml:"pageSize,omitempty"`
+}
+
+// dayWeekDuration matches the whole day and week durations time.ParseDuration does not know
+var dayWeekDuration = regexp.MustCompile(`^(\d+)([dw])$`)
+
+/*
+ParseHunt - Decode a hunt file, YAML or JSON
+Unknown fields are rejected so misspelled settings are not silently ignored.
+*/
+func ParseHunt(data []byte) (*Hunt, error) {
+	var hunt Hunt
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(&hunt)
+
+	if err == io.EOF {
+		return nil, errors.New("hunt file is empty")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if hunt.Name == "" {
+		return nil, errors.New("hunt has no name")
+	}
+
+	if hunt.Output.Format != "" && hunt.Output.Format != HuntFormatJson && hunt.Output.Format != HuntFormatCsv {
+		return nil, errors.New("hunt " + hunt.Name + " has unknown output format " + hunt.Output.Format)
+	}
+
+	return &hunt, nil
+}
+
+// LoadHunt - Read and decode the hunt file at path
+func LoadHunt(path string) (*Hunt, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hunt, err := ParseHunt(data)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return hunt, nil
+}
+
+// LoadHunts - Load every .yaml, .yml and .json hunt file of dir, sorted by file name
+func LoadHunts(dir string) ([]*Hunt, error) {
+	var paths []string
+
+	for _, pattern := range []string{"*.yaml", "*.yml", "*.json"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, matches...)
+	}
+
+	sort.Strings(paths)
+
+	hunts := make([]*Hunt, 0, len(paths))
+	names := make(map[string]string)
+
+	for _, path := range paths {
+		hunt, err := LoadHunt(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if other, ok := names[hunt.Name]; ok {
+			return nil, errors.New("hunt " + hunt.Name + " is defined in both " + other + " and " + path)
+		}
+
+		names[hunt.Name] = path
+		hunts = append(hunts, hunt)
+	}
+
+	return hunts, nil
+}
+
+/*
+BuildQuery - The Query of the hunt with its window resolved against now
+The window is added as a group of an ON_OR_AFTER and an ON_OR_BEFORE filter, so the query can also be
+exported with ExportWindowedJsonFileEvents. A query FFS would reject fails with a *QueryProblemsError.
+*/
+func (h *Hunt) BuildQuery(now time.Time) (Query, error) {
+	query := Query{GroupClause: string(ClauseAnd)}
+
+	if h.Query != "" && len(h.Groups) > 0 {
+		return Query{}, errors.New("hunt " + h.Name + " has both a query and groups")
+	}
+
+	//The query joins its own groups, overriding its clause would change its meaning
+	if h.Query != "" && h.GroupClause != "" {
+		return Query{}, errors.New("hunt " + h.Name + " has both a query and a groupClause")
+	}
+
+	if h.Query != "" {
+		var err error
+
+		query, err = ParseQuery(h.Query)
+
+		if err != nil {
+			return Query{}, fmt.Errorf("query of hunt %s: %w", h.Name, err)
+		}
+	}
+
+	if h.GroupClause != "" {
+		query.GroupClause = strings.ToUpper(h.GroupClause)
+	}
+
+	for i, huntGroup := range h.Groups {
+		group, err := huntGroup.group()
+
+		if err != nil {
+			return Query{}, fmt.Errorf("group %d of hunt %s: %w", i, h.Name, err)
+		}
+
+		query.Groups = append(query.Groups, group)
+	}
+
+	if h.Window != nil {
+		if query.GroupClause == string(ClauseOr) && len(query.Groups) > 0 {
+			return Query{}, errors.New("hunt " + h.Name + " joins its groups with OR, a window would not restrict them")
+		}
+
+		group, err := h.Window.group(now)
+
+		if err != nil {
+			return Query{}, fmt.Errorf("window of hunt %s: %w", h.Name, err)
+		}
+
+		query.Groups = append(query.Groups, group)
+	}
+
+	if h.Sort != nil {
+		query.SrtKey = h.Sort.Term
+		query.SrtDir = string(SortAsc)
+
+		if h.Sort.Direction != "" {
+			query.SrtDir = strings.ToLower(h.Sort.Direction)
+		}
+	}
+
+	if h.Output.PageSize != 0 {
+		query.PgSize = h.Output.PageSize
+	}
+
+	if problems := query.Validate(); problems != nil {
+		return Query{}, &QueryProblemsError{Problems: problems}
+	}
+
+	return query, nil
+}
+
+// group expands the filters, IS_ANY and NOT_IN must agree with the clause of the group
+func (g HuntGroup) group() (Group, error) {
+	clause := Clause(strings.ToUpper(g.Clause))
+
+	if clause == "" {
+		clause = ClauseAnd
+
+		//A group holding a single list takes the clause of the list
+		if len(g.Filters) == 1 && strings.EqualFold(g.Filters[0].Operator, string(OperatorIsAny)) {
+			clause = ClauseOr
+		}
+	}
+
+	group := Group{FilterClause: string(clause)}
+
+	for _, filter := range g.Filters {
+		operator := Operator(strings.ToUpper(filter.Operator))
+
+		if operator != OperatorIsAny && operator != OperatorNotIn {
+			if len(filter.Values) > 0 {
+				return Group{}, errors.New(string(operator) + " filter on " + filter.Term + " takes a value, not values")
+			}
+
+			group.Filters = append(group.Filters, Term(filter.Term).filter(operator, filter.Value))
+			continue
+		}
+
+		if filter.Value != "" || len(filter.Values) == 0 {
+			return Group{}, errors.New(string(operator) + " filter on " + filter.Term + " takes values, not a value")
+		}
+
+		list := MultiValueFilter{Term: Term(filter.Term), Operator: operator, Values: filter.Values}.Group()
+
+		if list.FilterClause != string(clause) {
+			return Group{}, errors.New(string(operator) + " filter on " + filter.Term + " cannot be joined with " + string(clause))
+		}
+
+		group.Filters = append(group.Filters, list.Filters...)
+	}
+
+	return group, nil
+}
+
+// group resolves the window against now
+func (w *HuntWindow) group(now time.Time) (Group, error) {
+	term := Term(w.Term)
+
+	if term == "" {
+		term = TermInsertionTimestamp
+	}
+
+	if w.Last != "" && w.Start != "" {
+		return Group{}, errors.New("window has both last and start")
+	}
+
+	start := w.Start
+
+	if w.Last != "" {
+		start = "now-" + w.Last
+	}
+
+	if start == "" {
+		return Group{}, errors.New("window needs last or start")
+	}
+
+	end := w.End
+
+	if end == "" {
+		end = "now"
+	}
+
+	startTime, err := resolveRelativeTime(start, now)
+
+	if err != nil {
+		return Group{}, err
+	}
+
+	endTime, err := resolveRelativeTime(end, now)
+
+	if err != nil {
+		return Group{}, err
+	}
+
+	if endTime.Before(startTime) {
+		return Group{}, errors.New("window ends before it starts")
+	}
+
+	return Group{Filters: []SearchFilter{term.OnOrAfter(startTime), term.OnOrBefore(endTime)}, FilterClause: string(ClauseAnd)}, nil
+}
+
+// resolveRelativeTime resolves "now", "now-<duration>" and timestamps
+func resolveRelativeTime(value string, now time.Time) (time.Time, error) {
+	if value == "now" {
+		return now, nil
+	}
+
+	if strings.HasPrefix(value, "now-") {
+		d, err := parseHuntDuration(strings.TrimPrefix(value, "now-"))
+
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return now.Add(-d), nil
+	}
+
+	timestamp, err := ParseTimestamp(value)
+
+	if err != nil {
+		return time.Time{}, errors.New(value + " is not now, now-<duration> or a timestamp")
+	}
+
+	return timestamp.Time, nil
+}
+
+// parseHuntDuration parses Go durations and whole days (7d) or weeks (2w)
+func parseHuntDuration(value string) (time.Duration, error) {
+	if match := dayWeekDuration.FindStringSubmatch(value); match != nil {
+		n, err := strconv.Atoi(match[1])
+
+		if err != nil {
+			return 0, err
+		}
+
+		if match[2] == "w" {
+			n *= 7
+		}
+
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+
+	if err != nil || d <= 0 {
+		return 0, errors.New(value + " is not a duration such as 36h, 7d or 2w")
+	}
+
+	return d, nil
+}
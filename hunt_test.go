@@ -0,0 +1,205 @@
+package ffs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const yamlHunt = `
+name: spreadsheets-to-usb
+description: Spreadsheets copied to removable media
+groups:
+  - filters:
+      - term: fileName
+        operator: IS_ANY
+        values: ["*.xlsx", "*.csv"]
+  - filters:
+      - term: exposure
+        operator: IS
+        value: RemovableMedia
+      - term: fileSize
+        operator: GREATER_THAN
+        value: "1024"
+window:
+  last: 7d
+sort:
+  term: insertionTimestamp
+  direction: desc
+output:
+  format: csv
+  path: spreadsheets.csv
+  pageSize: 500
+`
+
+const jsonHunt = `{
+	"name": "recent-uploads",
+	"query": "eventType IN (CREATED, MODIFIED) AND destinationCategory IS \"Cloud Storage\"",
+	"window": {"term": "eventTimestamp", "start": "now-36h", "end": "now-1h"},
+	"output": {"format": "json"}
+}`
+
+func TestHuntBuildQuery(t *testing.T) {
+	now := time.Date(2020, 3, 8, 12, 0, 0, 0, time.UTC)
+
+	hunt, err := ParseHunt([]byte(yamlHunt))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hunt.Output.Format != HuntFormatCsv || hunt.Output.Path != "spreadsheets.csv" {
+		t.Errorf("unexpected output %+v", hunt.Output)
+	}
+
+	query, err := hunt.BuildQuery(now)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := NewQueryBuilder().
+		Match(TermFileName.IsAny("*.xlsx", "*.csv")).
+		All(ExposureIs(ExposureRemovableMedia), TermFileSize.GreaterThan(1024)).
+		Between(TermInsertionTimestamp, now.Add(-7*24*time.Hour), now).
+		SortBy(TermInsertionTimestamp, SortDesc).
+		PageSize(500).
+		Build()
+
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("unexpected query\n%+v\nexpected\n%+v", query, want)
+	}
+
+	hunt, err = ParseHunt([]byte(jsonHunt))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query, err = hunt.BuildQuery(now)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = NewQueryBuilder().
+		Match(TermEventType.IsAny("CREATED", "MODIFIED")).
+		All(DestinationCategoryIs(DestinationCategoryCloudStorage)).
+		Between(TermEventTimestamp, now.Add(-36*time.Hour), now.Add(-time.Hour)).
+		Build()
+
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("unexpected query\n%+v\nexpected\n%+v", query, want)
+	}
+}
+
+func TestHuntErrors(t *testing.T) {
+	parseErrors := []string{
+		``,
+		`description: no name`,
+		"name: a\nwindw:\n  last: 7d",
+		"name: a\noutput:\n  format: xml",
+	}
+
+	for _, text := range parseErrors {
+		if _, err := ParseHunt([]byte(text)); err == nil {
+			t.Errorf("%q: expected an error", text)
+		}
+	}
+
+	buildErrors := []string{
+		"name: a\nquery: fileName IS a\ngroups:\n  - filters:\n      - {term: fileName, operator: IS, value: b}",
+		"name: a\ngroups:\n  - filters:\n      - {term: fileName, operator: IS_ANY, values: [a]}\n      - {term: fileSize, operator: LESS_THAN, value: '1'}",
+		"name: a\ngroups:\n  - filters:\n      - {term: fileName, operator: IS, values: [a]}",
+		"name: a\ngroups:\n  - filters:\n      - {term: fileName, operator: NOT_IN, value: a}",
+		"name: a\nquery: fileName IS a\nwindow: {last: 7days}",
+		"name: a\nquery: fileName IS a\nwindow: {last: 7d, start: now}",
+		"name: a\nquery: fileName IS a\nwindow: {start: now-1h, end: now-2h}",
+		"name: a\nquery: fileName IS a OR fileName IS b\nwindow: {last: 1d}",
+		"name: a\nquery: fileName IS",
+		"name: a\nquery: fileName IS a OR fileName IS b\ngroupClause: AND",
+	}
+
+	for _, text := range buildErrors {
+		hunt, err := ParseHunt([]byte(text))
+
+		if err != nil {
+			t.Errorf("%q: %v", text, err)
+			continue
+		}
+
+		if _, err = hunt.BuildQuery(time.Now()); err == nil {
+			t.Errorf("%q: expected an error", text)
+		}
+	}
+
+	hunt, err := ParseHunt([]byte("name: a\nquery: fileName ON_OR_AFTER 2020-01-01T00:00:00.000Z OR fileSize IS 1"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = hunt.BuildQuery(time.Now())
+
+	var syntaxErr *QuerySyntaxError
+
+	if !errors.As(err, &syntaxErr) || !strings.Contains(err.Error(), "hunt a") {
+		t.Errorf("expected a *QuerySyntaxError, got %v", err)
+	}
+
+	hunt, err = ParseHunt([]byte("name: a\nwindow: {term: fileName, last: 1d}"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = hunt.BuildQuery(time.Now())
+
+	var problemsErr *QueryProblemsError
+
+	if !errors.As(err, &problemsErr) {
+		t.Errorf("expected a *QueryProblemsError, got %v", err)
+	}
+}
+
+func TestLoadHunts(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"b.yaml":    yamlHunt,
+		"a.json":    jsonHunt,
+		"notes.txt": "not a hunt",
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hunts, err := LoadHunts(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hunts) != 2 || hunts[0].Name != "recent-uploads" || hunts[1].Name != "spreadsheets-to-usb" {
+		t.Errorf("unexpected hunts %+v", hunts)
+	}
+
+	if err = os.WriteFile(filepath.Join(dir, "c.yml"), []byte(jsonHunt), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = LoadHunts(dir); err == nil || !strings.Contains(err.Error(), "defined in both") {
+		t.Errorf("expected a duplicate name error, got %v", err)
+	}
+
+	if _, err = LoadHunt(filepath.Join(dir, "notes.txt")); err == nil || !strings.HasPrefix(err.Error(), filepath.Join(dir, "notes.txt")) {
+		t.Errorf("expected an error naming the file, got %v", err)
+	}
+}
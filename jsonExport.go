@@ -1,81 +1,82 @@
 package ffs
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"io/ioutil"
 	"log"
 	"net/http"
 )
 
 type JsonFileEvent struct {
-	Actor                      string       `json:"actor,omitempty"`
-	CloudDriveId               string       `json:"cloudDriveId,omitempty"`
-	CreateTimestamp            string       `json:"createTimestamp,omitempty"`
-	DestinationCategory        string       `json:"destinationCategory,omitempty"`
-	DestinationName            string       `json:"destinationName,omitempty"`
-	DetectionSourceAlias       string       `json:"detectionSourceAlias,omitempty"`
-	DeviceUid                  string       `json:"deviceUid,omitempty"`
-	DeviceUserName             string       `json:"deviceUserName,omitempty"`
-	DirectoryId                []string     `json:"directoryId,omitempty"`
-	DomainName                 string       `json:"domainName,omitempty"`
-	EmailDlpPolicyNames        []string     `json:"emailDlpPolicyNames,omitempty"`
-	EmailFrom                  string       `json:"emailFrom,omitempty"`
-	EmailRecipients            []string     `json:"emailRecipients,omitempty"`
-	EmailSender                string       `json:"emailSender,omitempty"`
-	EmailSubject               string       `json:"emailSubject,omitempty"`
-	EventId                    string       `json:"eventId"`
-	EventTimestamp             string       `json:"eventTimestamp,omitempty"`
-	EventType                  string       `json:"eventType,omitempty"`
-	Exposure                   []string     `json:"exposure,omitempty"`
-	FieldErrors                []FieldError `json:"fieldErrors,omitempty"`
-	FileCategory               string       `json:"fileCategory,omitempty"`
-	FileCategoryByBytes        string       `json:"fileCategoryByBytes,omitempty"`
-	FileCategoryByExtension    string       `json:"fileCategoryByExtension,omitempty"`
-	FileId                     string       `json:"fileId,omitempty"`
-	FileName                   string       `json:"fileName,omitempty"`
-	FileOwner                  string       `json:"fileOwner,omitempty"`
-	FilePath                   string       `json:"filePath,omitempty"`
-	FileSize                   *int64       `json:"fileSize,omitempty"`
-	FileType                   string       `json:"fileType,omitempty"`
-	InsertionTimestamp         string       `json:"insertionTimestamp,omitempty"`
-	Md5Checksum                string       `json:"md5Checksum,omitempty"`
-	MimeTypeByBytes            string       `json:"mimeTypeByBytes,omitempty"`
-	MimeTypeByExtension        string       `json:"mimeTypeByExtension,omitempty"`
-	MimeTypeMismatch           *bool        `json:"mimeTypeMismatch,omitempty"`
-	ModifyTimestamp            string       `json:"modifyTimestamp,omitempty"`
-	OperatingSystemUser        string       `json:"operatingSystemUser,omitempty"`
-	OsHostName                 string       `json:"osHostName,omitempty"`
-	OutsideActiveHours         *bool        `json:"outsideActiveHours,omitempty"`
-	PrintJobName               string       `json:"printJobName,omitempty"`
-	PrinterName                string       `json:"printerName,omitempty"`
-	PrivateIpAddresses         []string     `json:"privateIpAddresses,omitempty"`
-	ProcessName                string       `json:"processName,omitempty"`
-	ProcessOwner               string       `json:"processOwner,omitempty"`
-	PublicIpAddress            string       `json:"publicIpAddress,omitempty"`
-	RemoteActivity             string       `json:"remoteActivity,omitempty"`
-	RemovableMediaBusType      string       `json:"removableMediaBusType,omitempty"`
-	RemovableMediaCapacity     *int64       `json:"removableMediaCapacity,omitempty"`
-	RemovableMediaMediaName    string       `json:"removableMediaMediaName,omitempty"`
-	RemovableMediaName         string       `json:"removableMediaName,omitempty"`
-	RemovableMediaPartitionId  []string     `json:"removableMediaPartitionId,omitempty"`
-	RemovableMediaSerialNumber string       `json:"removableMediaSerialNumber,omitempty"`
-	RemovableMediaVendor       string       `json:"removableMediaVendor,omitempty"`
-	RemovableMediaVolumeName   []string     `json:"removableMediaVolumeName,omitempty"`
-	Sha256Checksum             string       `json:"sha256Checksum,omitempty"`
-	Shared                     string       `json:"shared,omitempty"`
-	SharedWith                 []SharedWith `json:"sharedWith,omitempty"`
-	SharingTypeAdded           []string     `json:"sharingTypeAdded,omitempty"`
-	Source                     string       `json:"source,omitempty"`
-	SyncDestination            string       `json:"syncDestination,omitempty"`
-	SyncDestinationUsername    []string     `json:"syncDestinationUsername,omitempty"`
-	TabUrl                     string       `json:"tabUrl,omitempty"`
-	Tabs                       []Tab        `json:"tabs,omitempty"`
-	Trusted                    *bool        `json:"trusted,omitempty"`
-	Url                        string       `json:"url,omitempty"`
-	UserUid                    string       `json:"userUid,omitempty"`
-	WindowTitle                []string     `json:"windowTitle,omitempty"`
+	Actor                      string                `json:"actor,omitempty"`
+	CloudDriveId               string                `json:"cloudDriveId,omitempty"`
+	CreateTimestamp            *Timestamp            `json:"createTimestamp,omitempty"`
+	DestinationCategory        DestinationCategory   `json:"destinationCategory,omitempty"`
+	DestinationName            string                `json:"destinationName,omitempty"`
+	DetectionSourceAlias       string                `json:"detectionSourceAlias,omitempty"`
+	DeviceUid                  string                `json:"deviceUid,omitempty"`
+	DeviceUserName             string                `json:"deviceUserName,omitempty"`
+	DirectoryId                []string              `json:"directoryId,omitempty"`
+	DomainName                 string                `json:"domainName,omitempty"`
+	EmailDlpPolicyNames        []string              `json:"emailDlpPolicyNames,omitempty"`
+	EmailFrom                  string                `json:"emailFrom,omitempty"`
+	EmailRecipients            []string              `json:"emailRecipients,omitempty"`
+	EmailSender                string                `json:"emailSender,omitempty"`
+	EmailSubject               string                `json:"emailSubject,omitempty"`
+	EventId                    string                `json:"eventId"`
+	EventTimestamp             *Timestamp            `json:"eventTimestamp,omitempty"`
+	EventType                  EventType             `json:"eventType,omitempty"`
+	Exposure                   []ExposureType        `json:"exposure,omitempty"`
+	FieldErrors                []FieldError          `json:"fieldErrors,omitempty"`
+	FileCategory               FileCategory          `json:"fileCategory,omitempty"`
+	FileCategoryByBytes        FileCategory          `json:"fileCategoryByBytes,omitempty"`
+	FileCategoryByExtension    FileCategory          `json:"fileCategoryByExtension,omitempty"`
+	FileId                     string                `json:"fileId,omitempty"`
+	FileName                   string                `json:"fileName,omitempty"`
+	FileOwner                  string                `json:"fileOwner,omitempty"`
+	FilePath                   string                `json:"filePath,omitempty"`
+	FileSize                   *int64                `json:"fileSize,omitempty"`
+	FileType                   string                `json:"fileType,omitempty"`
+	InsertionTimestamp         *Timestamp            `json:"insertionTimestamp,omitempty"`
+	Md5Checksum                string                `json:"md5Checksum,omitempty"`
+	MimeTypeByBytes            string                `json:"mimeTypeByBytes,omitempty"`
+	MimeTypeByExtension        string                `json:"mimeTypeByExtension,omitempty"`
+	MimeTypeMismatch           *bool                 `json:"mimeTypeMismatch,omitempty"`
+	ModifyTimestamp            *Timestamp            `json:"modifyTimestamp,omitempty"`
+	OperatingSystemUser        string                `json:"operatingSystemUser,omitempty"`
+	OsHostName                 string                `json:"osHostName,omitempty"`
+	OutsideActiveHours         *bool                 `json:"outsideActiveHours,omitempty"`
+	PrintJobName               string                `json:"printJobName,omitempty"`
+	PrinterName                string                `json:"printerName,omitempty"`
+	PrivateIpAddresses         []string              `json:"privateIpAddresses,omitempty"`
+	ProcessName                string                `json:"processName,omitempty"`
+	ProcessOwner               string                `json:"processOwner,omitempty"`
+	PublicIpAddress            string                `json:"publicIpAddress,omitempty"`
+	RemoteActivity             string                `json:"remoteActivity,omitempty"`
+	RemovableMediaBusType      RemovableMediaBusType `json:"removableMediaBusType,omitempty"`
+	RemovableMediaCapacity     *int64                `json:"removableMediaCapacity,omitempty"`
+	RemovableMediaMediaName    string                `json:"removableMediaMediaName,omitempty"`
+	RemovableMediaName         string                `json:"removableMediaName,omitempty"`
+	RemovableMediaPartitionId  []string              `json:"removableMediaPartitionId,omitempty"`
+	RemovableMediaSerialNumber string                `json:"removableMediaSerialNumber,omitempty"`
+	RemovableMediaVendor       string                `json:"removableMediaVendor,omitempty"`
+	RemovableMediaVolumeName   []string              `json:"removableMediaVolumeName,omitempty"`
+	Sha256Checksum             string                `json:"sha256Checksum,omitempty"`
+	Shared                     string                `json:"shared,omitempty"`
+	SharedWith                 []SharedWith          `json:"sharedWith,omitempty"`
+	SharingTypeAdded           []string              `json:"sharingTypeAdded,omitempty"`
+	Source                     Source                `json:"source,omitempty"`
+	SyncDestination            string                `json:"syncDestination,omitempty"`
+	SyncDestinationUsername    []string              `json:"syncDestinationUsername,omitempty"`
+	TabUrl                     string                `json:"tabUrl,omitempty"`
+	Tabs                       []Tab                 `json:"tabs,omitempty"`
+	Trusted                    *bool                 `json:"trusted,omitempty"`
+	Url                        string                `json:"url,omitempty"`
+	UserUid                    string                `json:"userUid,omitempty"`
+	WindowTitle                []string              `json:"windowTitle,omitempty"`
+	//Extras holds the properties this struct has no field for, they are re-emitted when marshaling
+	Extras map[string]json.RawMessage `json:"-"`
 }
 
 type FieldError struct {
@@ -118,92 +119,100 @@ func GetJsonFileEventResponse(resp *http.Response) (*JsonFileEventResponse, erro
 	return &eventResponse, nil
 }
 
+/*
+GetJsonFileEvents - Function to get all JSON file events for a query from the FFS search endpoint
+Every page of results is gathered, debugging logs the page tokens to the standard logger.
+On error the returned token is the page which failed, see Client.ExportJsonFileEvents for budgeted, resumable exports.
+*/
 func GetJsonFileEvents(authData AuthData, ffsURI string, query Query, pgToken string, debugging bool) (*[]JsonFileEvent, string, error) {
+	return GetJsonFileEventsContext(context.Background(), authData, ffsURI, query, pgToken, debugging)
+}
+
+// GetJsonFileEventsContext - GetJsonFileEvents which can be cancelled through ctx, cancellation is checked before every page
+func GetJsonFileEventsContext(ctx context.Context, authData AuthData, ffsURI string, query Query, pgToken string, debugging bool) (*[]JsonFileEvent, string, error) {
+	opts := []Option{WithFFSURL(ffsURI)}
+
+	if debugging {
+		opts = append(opts, WithLogger(log.Default()))
+	}
+
+	return NewClient(opts...).GetJsonFileEventsContext(ctx, authData, query, pgToken)
+}
+
+// GetJsonFileEvents - Get all JSON file events for a query from the client's FFS search endpoint
+func (c *Client) GetJsonFileEvents(authData AuthData, query Query, pgToken string) (*[]JsonFileEvent, string, error) {
+	return c.GetJsonFileEventsContext(context.Background(), authData, query, pgToken)
+}
+
+// GetJsonFileEventsContext - GetJsonFileEvents which can be cancelled through ctx, cancellation is checked before every page
+func (c *Client) GetJsonFileEventsContext(ctx context.Context, authData AuthData, query Query, pgToken string) (*[]JsonFileEvent, string, error) {
 	var jsonFileEvents []JsonFileEvent
 
 	if pgToken != "" {
 		query.PgToken = pgToken
 	}
 
-	//Validate jsonQuery is valid JSON
-	ffsQuery, err := json.Marshal(query)
-	if err != nil {
-		return nil, "", errors.New("jsonQuery is not in a valid json format")
-	}
+	//Split queries have no single page token to resume from, splitQuery rejects queries with a token
+	if c.maxFilters > 0 && filterCount(query) > c.maxFilters {
+		events := c.JsonFileEvents(ctx, authData, query)
 
-	//Make sure authData token is not ""
-	if authData.AccessToken == "" {
-		return nil, "", errors.New("authData cannot be nil")
-	}
+		for events.Next() {
+			jsonFileEvents = append(jsonFileEvents, events.Event())
+		}
 
-	//Query ffsURI with authData API token and jsonQuery body
-	req, err := http.NewRequest("POST", ffsURI, bytes.NewReader(ffsQuery))
+		if events.Err() != nil {
+			return nil, "", events.Err()
+		}
 
-	//Handle request errors
-	if err != nil {
-		return nil, "", err
+		return &jsonFileEvents, "", nil
 	}
 
-	//Set request headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer  "+authData.AccessToken)
+	//Gather the events of every page
+	pages := c.JsonFileEventPages(ctx, authData, query)
 
-	//Get Response
-	resp, err := http.DefaultClient.Do(req)
+	for pages.Next() {
+		jsonFileEvents = append(jsonFileEvents, pages.Page().FileEvents...)
+	}
 
-	//Handle response errors
-	if err != nil {
-		return nil, "", err
+	//Return the token of the failed page so the caller can resume from it
+	if pages.Err() != nil {
+		return nil, pages.PgToken(), pages.Err()
 	}
 
-	//defer body close
-	defer resp.Body.Close()
+	return &jsonFileEvents, "", nil
+}
 
-	//Make sure http status code is 200
-	if resp.StatusCode != http.StatusOK {
-		return nil, "", errors.New("Error with gathering file events POST: " + resp.Status)
+// getJsonFileEventPage requests a single page of file events for query
+func (c *Client) getJsonFileEventPage(ctx context.Context, authData AuthData, query Query) (*JsonFileEventResponse, error) {
+	//Stop before requesting another page if ctx is done
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
 	}
 
-	fileEventResponse, err := GetJsonFileEventResponse(resp)
+	resp, err := c.postQuery(ctx, c.ffsURL, authData, query)
 
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	if fileEventResponse.Problems != nil {
-		problems, err := json.Marshal(fileEventResponse.Problems)
+	//defer body close
+	defer resp.Body.Close()
+
+	fileEventResponse, err := GetJsonFileEventResponse(resp)
 
-		if err != nil {
-			return nil, "", err
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
 
-		return nil, "", errors.New(string(problems))
+		return nil, err
 	}
 
-	if len(fileEventResponse.FileEvents) == 0 {
-		fileEventResponse.FileEvents = nil
-	} else {
-		jsonFileEvents = append(jsonFileEvents, fileEventResponse.FileEvents...)
+	if fileEventResponse.Problems != nil {
+		return nil, &QueryProblemsError{Problems: fileEventResponse.Problems}
 	}
 
-	var nextJsonFileEvents *[]JsonFileEvent
-
-	if fileEventResponse.NextPgToken != "" {
-		if debugging {
-			log.Print("Next Page Token: ")
-			log.Println(fileEventResponse.NextPgToken)
-		}
-
-		nextJsonFileEvents, _, err = GetJsonFileEvents(authData, ffsURI, query, fileEventResponse.NextPgToken, debugging)
-
-		if err != nil {
-			return nil, "", err
-		}
+	c.unknownFields.report(fileEventResponse.FileEvents)
 
-		if nextJsonFileEvents != nil && len(*nextJsonFileEvents) != 0 {
-			jsonFileEvents = append(jsonFileEvents, *nextJsonFileEvents...)
-		}
-	}
-
-	return &jsonFileEvents, "", nil
+	return fileEventResponse, nil
 }
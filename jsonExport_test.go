@@ -1,11 +1,19 @@
 package ffs
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestGetJsonFileEvents(t *testing.T) {
+	skipWithoutCredentials(t)
+
 	authData, err := GetAuthData(authUri, username, password)
 
 	if err != nil {
@@ -33,3 +41,49 @@ func TestGetJsonFileEvents(t *testing.T) {
 		}
 	}
 }
+
+func TestGetJsonFileEventsContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		//Cancel while the first page is being served, the second page must never be requested
+		cancel()
+
+		_ = json.NewEncoder(w).Encode(JsonFileEventResponse{FileEvents: []JsonFileEvent{{EventId: "1"}}, NextPgToken: "next"})
+	}))
+	defer server.Close()
+
+	_, _, err := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL)).GetJsonFileEventsContext(ctx, AuthData{AccessToken: "token"}, jsonQuery, "")
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestGetJsonFileEventsContextDeadline(t *testing.T) {
+	done := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL)).GetJsonFileEventsContext(ctx, AuthData{AccessToken: "token"}, jsonQuery, "")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
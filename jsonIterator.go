@@ -0,0 +1,207 @@
+package ffs
+
+import (
+	"context"
+)
+
+// FFS JSON Pagination
+
+/*
+JsonFileEventPages - Pull-style iterator over the pages of a JSON file event query
+Only the current page is held in memory, call Next until it returns false and then check Err.
+Queries over the client's filter limit fail with ErrTooManyFilters, use JsonFileEvents to split them.
+
+	pages := client.JsonFileEventPages(ctx, authData, query)
+	for pages.Next() {
+		process(pages.Page().FileEvents)
+	}
+	if err := pages.Err(); err != nil {
+		...
+	}
+*/
+type JsonFileEventPages struct {
+	ctx      context.Context
+	client   *Client
+	authData AuthData
+	query    Query
+
+	page    *JsonFileEventResponse
+	started bool
+	done    bool
+	err     error
+}
+
+// JsonFileEventPages - Iterate over the pages of query, starting from query.PgToken
+func (c *Client) JsonFileEventPages(ctx context.Context, authData AuthData, query Query) *JsonFileEventPages {
+	return &JsonFileEventPages{
+		ctx:      ctx,
+		client:   c,
+		authData: authData,
+		query:    query,
+	}
+}
+
+// Next - Fetch the next page, returns false once every page was read or a request failed
+func (p *JsonFileEventPages) Next() bool {
+	if p.done {
+		return false
+	}
+
+	if p.started {
+		nextPgToken := p.NextPgToken()
+
+		//An empty or repeated token means there are no more pages
+		if nextPgToken == "" || nextPgToken == p.query.PgToken {
+			p.done = true
+			p.page = nil
+			return false
+		}
+
+		p.client.logf("Next Page Token: %s", nextPgToken)
+		p.query.PgToken = nextPgToken
+	}
+
+	p.started = true
+
+	page, err := p.client.getJsonFileEventPage(p.ctx, p.authData, p.query)
+
+	if err != nil {
+		p.err = err
+		p.done = true
+		p.page = nil
+		return false
+	}
+
+	p.page = page
+
+	return true
+}
+
+// Page - The current page, valid until the next call to Next
+func (p *JsonFileEventPages) Page() *JsonFileEventResponse {
+	return p.page
+}
+
+// PgToken - Token which requested the current page, resubmit it to fetch the same page again
+func (p *JsonFileEventPages) PgToken() string {
+	return p.query.PgToken
+}
+
+// NextPgToken - Token of the page after the current one, "" on the last page
+func (p *JsonFileEventPages) NextPgToken() string {
+	if p.page == nil {
+		return ""
+	}
+
+	return p.page.NextPgToken
+}
+
+// TotalCount - Number of events matching the query as reported by the current page, nil if unknown
+func (p *JsonFileEventPages) TotalCount() *int64 {
+	if p.page == nil {
+		return nil
+	}
+
+	return p.page.TotalCount
+}
+
+// Err - The error which stopped the iteration, nil if every page was read
+func (p *JsonFileEventPages) Err() error {
+	return p.err
+}
+
+/*
+JsonFileEventIterator - Pull-style iterator over the individual events of a JSON file event query
+Pages are fetched as they are needed, so memory use is bounded by the page size.
+Queries with more filters than the client allows are split into several queries (see WithMaxFiltersPerQuery)
+which are read one after another, events are then de-duplicated by EventId and only ordered within each query.
+An event can match several of the split queries, so every EventId returned so far is kept and memory use grows
+with the number of events.
+
+	events := client.JsonFileEvents(ctx, authData, query)
+	for events.Next() {
+		process(events.Event())
+	}
+	if err := events.Err(); err != nil {
+		...
+	}
+*/
+type JsonFileEventIterator struct {
+	pages  *JsonFileEventPages
+	events []JsonFileEvent
+	event  JsonFileEvent
+
+	//queries left to read and the EventIds already returned when query was split
+	queries []Query
+	seen    map[string]struct{}
+}
+
+// JsonFileEvents - Iterate over every event matching query, starting from query.PgToken
+func (c *Client) JsonFileEvents(ctx context.Context, authData AuthData, query Query) *JsonFileEventIterator {
+	queries, err := splitQuery(query, c.maxFilters)
+
+	if err != nil {
+		pages := c.JsonFileEventPages(ctx, authData, query)
+		pages.err = err
+		pages.done = true
+
+		return &JsonFileEventIterator{pages: pages}
+	}
+
+	it := &JsonFileEventIterator{pages: c.JsonFileEventPages(ctx, authData, queries[0])}
+
+	if len(queries) > 1 {
+		c.logf("Splitting query with %d filters into %d queries", filterCount(query), len(queries))
+		it.queries = queries[1:]
+		it.seen = make(map[string]struct{})
+	}
+
+	return it
+}
+
+// Next - Advance to the next event, fetching the next page when the current one is used up
+func (it *JsonFileEventIterator) Next() bool {
+	for {
+		for len(it.events) == 0 {
+			if it.pages.Next() {
+				it.events = it.pages.Page().FileEvents
+				continue
+			}
+
+			if it.pages.Err() != nil || len(it.queries) == 0 {
+				it.event = JsonFileEvent{}
+				return false
+			}
+
+			it.pages = it.pages.client.JsonFileEventPages(it.pages.ctx, it.pages.authData, it.queries[0])
+			it.queries = it.queries[1:]
+		}
+
+		it.event = it.events[0]
+		it.events = it.events[1:]
+
+		if it.seen == nil || it.event.EventId == "" {
+			return true
+		}
+
+		if _, ok := it.seen[it.event.EventId]; !ok {
+			it.seen[it.event.EventId] = struct{}{}
+			return true
+		}
+	}
+}
+
+// Event - The current event
+func (it *JsonFileEventIterator) Event() JsonFileEvent {
+	return it.event
+}
+
+// Pages - The underlying page iterator, for NextPgToken and TotalCount of the current page of the current query
+func (it *JsonFileEventIterator) Pages() *JsonFileEventPages {
+	return it.pages
+}
+
+// Err - The error which stopped the iteration, nil if every event was read
+func (it *JsonFileEventIterator) Err() error {
+	return it.pages.Err()
+}
@@ -0,0 +1,94 @@
+package ffs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newPagedServer serves pages keyed by the pgToken of the request, unknown tokens get a 400
+func newPagedServer(t *testing.T, pages map[string]JsonFileEventResponse) (*httptest.Server, *Client) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query Query
+
+		err := json.NewDecoder(r.Body).Decode(&query)
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		page, ok := pages[query.PgToken]
+
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+
+	return server, NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL), WithRetryPolicy(NoRetry))
+}
+
+func TestJsonFileEventPages(t *testing.T) {
+	total := int64(3)
+
+	server, c := newPagedServer(t, map[string]JsonFileEventResponse{
+		"":  {FileEvents: []JsonFileEvent{{EventId: "1"}}, NextPgToken: "b", TotalCount: &total},
+		"b": {FileEvents: []JsonFileEvent{{EventId: "2"}, {EventId: "3"}}, NextPgToken: "", TotalCount: &total},
+	})
+	defer server.Close()
+
+	pages := c.JsonFileEventPages(context.Background(), AuthData{AccessToken: "token"}, jsonQuery)
+
+	var tokens []string
+
+	for pages.Next() {
+		if *pages.TotalCount() != 3 {
+			t.Errorf("unexpected total count %d", *pages.TotalCount())
+		}
+
+		tokens = append(tokens, pages.PgToken()+">"+pages.NextPgToken())
+	}
+
+	if pages.Err() != nil {
+		t.Fatal(pages.Err())
+	}
+
+	if strings.Join(tokens, ",") != ">b,b>" {
+		t.Error("unexpected page tokens: " + strings.Join(tokens, ","))
+	}
+}
+
+func TestJsonFileEventIterator(t *testing.T) {
+	server, c := newPagedServer(t, map[string]JsonFileEventResponse{
+		"":  {FileEvents: []JsonFileEvent{{EventId: "1"}, {EventId: "2"}}, NextPgToken: "b"},
+		"b": {NextPgToken: "c"},
+		"c": {FileEvents: []JsonFileEvent{{EventId: "3"}}, NextPgToken: "missing"},
+	})
+	defer server.Close()
+
+	events := c.JsonFileEvents(context.Background(), AuthData{AccessToken: "token"}, jsonQuery)
+
+	var ids []string
+
+	for events.Next() {
+		ids = append(ids, events.Event().EventId)
+	}
+
+	//Events before the failing page are still delivered
+	if strings.Join(ids, ",") != "1,2,3" {
+		t.Error("unexpected events: " + strings.Join(ids, ","))
+	}
+
+	if events.Err() == nil {
+		t.Error("expected the missing page to fail the iteration")
+	}
+
+	if events.Next() {
+		t.Error("expected Next to keep returning false after an error")
+	}
+}
@@ -0,0 +1,161 @@
+package ffs
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// FFS Resumable JSON Exports
+
+// ExportOptions - Budget and progress hook for a single ExportJsonFileEvents call
+type ExportOptions struct {
+	//MaxPages stops the export after this many pages, 0 means no limit
+	MaxPages int
+	//MaxEvents stops the export after the page which reaches this many events (pages are never split), 0 means no limit
+	MaxEvents int
+	//OnPage is called after every page with its events and the checkpoint which resumes after it, returning an error stops the export
+	OnPage func(fileEvents []JsonFileEvent, checkpoint Checkpoint) error
+}
+
+// ExportResult - Events gathered by ExportJsonFileEvents and where to continue from
+type ExportResult struct {
+	FileEvents []JsonFileEvent
+	//Pages is the number of pages gathered by this call
+	Pages int
+	//TotalCount as reported by the last page, nil if unknown
+	TotalCount *int64
+	//Checkpoint resumes the export after the last page gathered, Checkpoint.Done is set once every page was read
+	Checkpoint Checkpoint
+}
+
+/*
+Checkpoint - Serializable resume point of an export
+Query has PgToken set to the next page to request, persist the checkpoint and pass it
+to ResumeJsonFileEvents to continue after a crash or deploy. A Done checkpoint has an empty
+PgToken, passing its Query to ExportJsonFileEvents would start the export over.
+*/
+type Checkpoint struct {
+	Query Query `json:"query"`
+	Done  bool  `json:"done"`
+}
+
+// NextPgToken - Token of the next page to request, "" once Done
+func (cp Checkpoint) NextPgToken() string {
+	if cp.Done {
+		return ""
+	}
+
+	return cp.Query.PgToken
+}
+
+/*
+ExportJsonFileEvents - Gather the events of query starting from query.PgToken, within the budget set by opts
+The result always carries a Checkpoint, even together with an error, which resumes from the first page
+that was not gathered. Pages gathered before an error are returned as well.
+An empty query.PgToken starts from the first page, continue from a Checkpoint with ResumeJsonFileEvents.
+*/
+func (c *Client) ExportJsonFileEvents(ctx context.Context, authData AuthData, query Query, opts ExportOptions) (*ExportResult, error) {
+	result := &ExportResult{Checkpoint: Checkpoint{Query: query}}
+
+	pages := c.JsonFileEventPages(ctx, authData, query)
+
+	for (opts.MaxPages == 0 || result.Pages < opts.MaxPages) && (opts.MaxEvents == 0 || len(result.FileEvents) < opts.MaxEvents) {
+		if !pages.Next() {
+			if pages.Err() != nil {
+				return result, pages.Err()
+			}
+
+			break
+		}
+
+		page := pages.Page()
+
+		result.FileEvents = append(result.FileEvents, page.FileEvents...)
+		result.Pages++
+		result.TotalCount = page.TotalCount
+
+		//The checkpoint moves past this page, an empty or repeated token means it was the last one
+		result.Checkpoint.Query.PgToken = page.NextPgToken
+		result.Checkpoint.Done = page.NextPgToken == "" || page.NextPgToken == pages.PgToken()
+
+		if opts.OnPage != nil {
+			err := opts.OnPage(page.FileEvents, result.Checkpoint)
+
+			if err != nil {
+				return result, err
+			}
+		}
+
+		if result.Checkpoint.Done {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+/*
+ResumeJsonFileEvents - Continue the export of checkpoint within the budget set by opts
+A Done checkpoint returns an empty result carrying the same checkpoint without sending a request.
+*/
+func (c *Client) ResumeJsonFileEvents(ctx context.Context, authData AuthData, checkpoint Checkpoint, opts ExportOptions) (*ExportResult, error) {
+	if checkpoint.Done {
+		return &ExportResult{Checkpoint: checkpoint}, nil
+	}
+
+	return c.ExportJsonFileEvents(ctx, authData, checkpoint.Query, opts)
+}
+
+// ReadCheckpointFile - Load a checkpoint written by WriteCheckpointFile
+func ReadCheckpointFile(path string) (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var checkpoint Checkpoint
+
+	err = json.Unmarshal(data, &checkpoint)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+// WriteCheckpointFile - Persist checkpoint as JSON, the file is replaced atomically so a crash never leaves a partial checkpoint
+func WriteCheckpointFile(path string, checkpoint Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
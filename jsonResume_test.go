@@ -0,0 +1,113 @@
+package ffs
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportJsonFileEventsBudgetAndResume(t *testing.T) {
+	pages := map[string]JsonFileEventResponse{
+		"":  {FileEvents: []JsonFileEvent{{EventId: "1"}, {EventId: "2"}}, NextPgToken: "b"},
+		"b": {FileEvents: []JsonFileEvent{{EventId: "3"}}, NextPgToken: "c"},
+		"c": {FileEvents: []JsonFileEvent{{EventId: "4"}}},
+	}
+
+	server, c := newPagedServer(t, pages)
+	defer server.Close()
+
+	authData := AuthData{AccessToken: "token"}
+
+	result, err := c.ExportJsonFileEvents(context.Background(), authData, jsonQuery, ExportOptions{MaxPages: 1})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.FileEvents) != 2 || result.Checkpoint.Done || result.Checkpoint.NextPgToken() != "b" {
+		t.Fatalf("unexpected first call result: %+v", result)
+	}
+
+	//Persist and reload the checkpoint as if the process restarted
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	err = WriteCheckpointFile(path, result.Checkpoint)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkpoint, err := ReadCheckpointFile(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Simulate a failure on page c, the checkpoint must point at it
+	delete(pages, "c")
+
+	result, err = c.ResumeJsonFileEvents(context.Background(), authData, *checkpoint, ExportOptions{})
+
+	if err == nil {
+		t.Fatal("expected page c to fail")
+	}
+
+	if len(result.FileEvents) != 1 || result.FileEvents[0].EventId != "3" || result.Checkpoint.NextPgToken() != "c" {
+		t.Fatalf("unexpected partial result: %+v", result)
+	}
+
+	pages["c"] = JsonFileEventResponse{FileEvents: []JsonFileEvent{{EventId: "4"}}}
+
+	result, err = c.ExportJsonFileEvents(context.Background(), authData, result.Checkpoint.Query, ExportOptions{MaxEvents: 10})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.FileEvents) != 1 || result.FileEvents[0].EventId != "4" || !result.Checkpoint.Done {
+		t.Fatalf("unexpected resumed result: %+v", result)
+	}
+
+	//Resuming a finished export must not start it over, every page now fails if requested
+	for token := range pages {
+		delete(pages, token)
+	}
+
+	result, err = c.ResumeJsonFileEvents(context.Background(), authData, result.Checkpoint, ExportOptions{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.FileEvents) != 0 || result.Pages != 0 || !result.Checkpoint.Done {
+		t.Errorf("unexpected result resuming a done checkpoint: %+v", result)
+	}
+}
+
+func TestExportJsonFileEventsMaxEvents(t *testing.T) {
+	server, c := newPagedServer(t, map[string]JsonFileEventResponse{
+		"":  {FileEvents: []JsonFileEvent{{EventId: "1"}, {EventId: "2"}}, NextPgToken: "b"},
+		"b": {FileEvents: []JsonFileEvent{{EventId: "3"}, {EventId: "4"}}, NextPgToken: "c"},
+		"c": {FileEvents: []JsonFileEvent{{EventId: "5"}}},
+	})
+	defer server.Close()
+
+	var checkpoints []string
+
+	result, err := c.ExportJsonFileEvents(context.Background(), AuthData{AccessToken: "token"}, jsonQuery, ExportOptions{
+		MaxEvents: 3,
+		OnPage: func(fileEvents []JsonFileEvent, checkpoint Checkpoint) error {
+			checkpoints = append(checkpoints, checkpoint.NextPgToken())
+			return nil
+		},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Pages are never split, so the budget is reached at the end of the second page
+	if len(result.FileEvents) != 4 || result.Pages != 2 || len(checkpoints) != 2 || checkpoints[1] != "c" {
+		t.Errorf("unexpected result: %d events, %d pages, checkpoints %v", len(result.FileEvents), result.Pages, checkpoints)
+	}
+}
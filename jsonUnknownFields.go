@@ -0,0 +1,160 @@
+package ffs
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FFS JSON Unknown Fields
+
+// knownJsonFields holds the lower cased json names of the JsonFileEvent fields, encoding/json matches them case insensitively
+var knownJsonFields = jsonFieldNames(reflect.TypeOf(JsonFileEvent{}))
+
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+
+		if name != "" && name != "-" {
+			names[strings.ToLower(name)] = true
+		}
+	}
+
+	return names
+}
+
+// jsonFileEventFields has the fields of JsonFileEvent without its (Un)MarshalJSON methods
+type jsonFileEventFields JsonFileEvent
+
+// UnmarshalJSON - Decode the known fields and keep every other property in Extras
+func (e *JsonFileEvent) UnmarshalJSON(data []byte) error {
+	var fields jsonFileEventFields
+
+	err := json.Unmarshal(data, &fields)
+
+	if err != nil {
+		return err
+	}
+
+	var properties map[string]json.RawMessage
+
+	err = json.Unmarshal(data, &properties)
+
+	if err != nil {
+		return err
+	}
+
+	*e = JsonFileEvent(fields)
+	e.Extras = nil
+
+	for name, value := range properties {
+		if knownJsonFields[strings.ToLower(name)] {
+			continue
+		}
+
+		if e.Extras == nil {
+			e.Extras = make(map[string]json.RawMessage)
+		}
+
+		e.Extras[name] = value
+	}
+
+	return nil
+}
+
+// MarshalJSON - Encode the known fields followed by Extras, sorted by name
+func (e JsonFileEvent) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(jsonFileEventFields(e))
+
+	if err != nil || len(e.Extras) == 0 {
+		return data, err
+	}
+
+	names := make([]string, 0, len(e.Extras))
+
+	for name := range e.Extras {
+		//A known field is never emitted twice
+		if !knownJsonFields[strings.ToLower(name)] {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+
+	buf.Write(data[:len(data)-1])
+
+	for _, name := range names {
+		key, err := json.Marshal(name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(e.Extras[name])
+
+		if err != nil {
+			return nil, err
+		}
+
+		buf.WriteByte(',')
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// unknownFieldTracker reports each unknown field name once
+type unknownFieldTracker struct {
+	mu   sync.Mutex
+	seen map[string]bool
+	fn   func(field string)
+}
+
+/*
+WithUnknownFieldHook - Call fn the first time a file event from the JSON API has a field JsonFileEvent does not know
+Each name is reported once per Client, the values are kept in JsonFileEvent.Extras either way.
+*/
+func WithUnknownFieldHook(fn func(field string)) Option {
+	return func(c *Client) {
+		c.unknownFields = &unknownFieldTracker{seen: make(map[string]bool), fn: fn}
+	}
+}
+
+// report calls the hook for the Extras names of events not seen before
+func (u *unknownFieldTracker) report(events []JsonFileEvent) {
+	if u == nil {
+		return
+	}
+
+	var fresh []string
+
+	u.mu.Lock()
+
+	for _, event := range events {
+		for name := range event.Extras {
+			if !u.seen[name] {
+				u.seen[name] = true
+				fresh = append(fresh, name)
+			}
+		}
+	}
+
+	u.mu.Unlock()
+
+	sort.Strings(fresh)
+
+	for _, name := range fresh {
+		u.fn(name)
+	}
+}
@@ -0,0 +1,84 @@
+package ffs
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJsonFileEventKeepsUnknownFields(t *testing.T) {
+	data := `{"eventId":"a","fileName":"report.xlsx","riskScore":9,"riskIndicators":[{"name":"zip"}]}`
+
+	var event JsonFileEvent
+
+	err := json.Unmarshal([]byte(data), &event)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.EventId != "a" || event.FileName != "report.xlsx" || len(event.Extras) != 2 || string(event.Extras["riskScore"]) != "9" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	out, err := json.Marshal(event)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(out) != `{"eventId":"a","fileName":"report.xlsx","riskIndicators":[{"name":"zip"}],"riskScore":9}` {
+		t.Errorf("unexpected json: %s", out)
+	}
+
+	var decoded JsonFileEvent
+
+	if err := json.Unmarshal(out, &decoded); err != nil || !reflect.DeepEqual(decoded, event) {
+		t.Errorf("round trip changed the event: %+v %v", decoded, err)
+	}
+
+	//Known fields are matched case insensitively like encoding/json does, and never duplicated on marshal
+	event = JsonFileEvent{EventId: "b", Extras: map[string]json.RawMessage{"eventId": json.RawMessage(`"c"`)}}
+
+	out, err = json.Marshal(event)
+
+	if err != nil || string(out) != `{"eventId":"b"}` {
+		t.Errorf("unexpected json: %s %v", out, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"EventID":"d"}`), &event); err != nil || event.EventId != "d" || event.Extras != nil {
+		t.Errorf("unexpected event: %+v %v", event, err)
+	}
+}
+
+func TestUnknownFieldHook(t *testing.T) {
+	server, c := newPagedServer(t, map[string]JsonFileEventResponse{
+		"": {FileEvents: []JsonFileEvent{
+			{EventId: "1", Extras: map[string]json.RawMessage{"riskScore": json.RawMessage("1")}},
+			{EventId: "2", Extras: map[string]json.RawMessage{"riskScore": json.RawMessage("2"), "riskSeverity": json.RawMessage(`"LOW"`)}},
+		}, NextPgToken: "b"},
+		"b": {FileEvents: []JsonFileEvent{{EventId: "3", Extras: map[string]json.RawMessage{"riskScore": json.RawMessage("3")}}}},
+	})
+	defer server.Close()
+
+	var reported []string
+
+	WithUnknownFieldHook(func(field string) { reported = append(reported, field) })(c)
+
+	events := c.JsonFileEvents(context.Background(), AuthData{AccessToken: "token"}, jsonQuery)
+
+	n := 0
+
+	for events.Next() {
+		n++
+	}
+
+	if events.Err() != nil || n != 3 {
+		t.Fatalf("read %d events: %v", n, events.Err())
+	}
+
+	if !reflect.DeepEqual(reported, []string{"riskScore", "riskSeverity"}) {
+		t.Errorf("unexpected reported fields: %v", reported)
+	}
+}
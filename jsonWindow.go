@@ -0,0 +1,258 @@
+package ffs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FFS Time Window Splitting
+
+// MaxResultsPerQuery - Code42 FFS API cap on the number of results a single query returns
+const MaxResultsPerQuery = 200000
+
+// ffsTimestampFormat is the millisecond precision UTC format FFS expects for timestamp filter values
+const ffsTimestampFormat = "2006-01-02T15:04:05.000Z"
+
+// WindowedExportOptions - Settings for ExportWindowedJsonFileEvents
+type WindowedExportOptions struct {
+	//Term is the timestamp term whose range is split, "" picks insertionTimestamp or else eventTimestamp
+	Term string
+	//MaxResults is the largest slice exported as a single query, defaults to MaxResultsPerQuery
+	MaxResults int64
+	//MinWindow is the smallest slice which is still split further, defaults to 1ms (the FFS timestamp precision)
+	MinWindow time.Duration
+}
+
+// timeRange locates the ON_OR_AFTER and ON_OR_BEFORE filters of a term inside a query
+type timeRange struct {
+	term                 string
+	start, end           time.Time
+	startGroup, endGroup int
+	startIdx, endIdx     int
+}
+
+/*
+ExportWindowedJsonFileEvents - Export every event of query, getting past the 200,000 results per query cap
+query must contain an ON_OR_AFTER and an ON_OR_BEFORE filter on insertionTimestamp or eventTimestamp
+which restrict the results, so neither in a group joined with OR nor in a query whose groups are joined with OR.
+The time range is bisected until each slice's TotalCount fits under the cap, and the slices are exported
+in order. Slices do not overlap, each one is de-duplicated by EventId on its own, so every event is passed to fn
+exactly once while only the EventIds of the current slice are kept. Returning an error from fn stops the export.
+query.PgToken is ignored, every slice is exported from its first page.
+*/
+func (c *Client) ExportWindowedJsonFileEvents(ctx context.Context, authData AuthData, query Query, opts WindowedExportOptions, fn func(JsonFileEvent) error) error {
+	if opts.MaxResults <= 0 {
+		opts.MaxResults = MaxResultsPerQuery
+	}
+
+	if opts.MinWindow <= 0 {
+		opts.MinWindow = time.Millisecond
+	}
+
+	terms := []string{opts.Term}
+
+	if opts.Term == "" {
+		terms = []string{string(TermInsertionTimestamp), string(TermEventTimestamp)}
+	}
+
+	var window *timeRange
+	var err error
+
+	for _, term := range terms {
+		window, err = findTimeRange(query, term)
+
+		//Only fall back to the next term if the query has no range on this one
+		if err == nil || hasRangeFilter(query, term) {
+			break
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return c.exportWindow(ctx, authData, query, window, window.start, window.end, opts, fn)
+}
+
+// exportWindow exports [start, end], splitting it in two halves if it holds more than opts.MaxResults events
+func (c *Client) exportWindow(ctx context.Context, authData AuthData, query Query, window *timeRange, start time.Time, end time.Time, opts WindowedExportOptions, fn func(JsonFileEvent) error) error {
+	sliceQuery := window.apply(query, start, end)
+
+	count, err := c.countJsonFileEvents(ctx, authData, sliceQuery)
+
+	if err != nil {
+		return err
+	}
+
+	if count > opts.MaxResults {
+		//Halves do not overlap, the second one starts one millisecond after the first one ends
+		mid := start.Add(end.Sub(start) / 2).Truncate(time.Millisecond)
+
+		if end.Sub(start) <= opts.MinWindow || !mid.Before(end) {
+			return fmt.Errorf("%d events between %s and %s exceed the %d results per query cap and the window cannot be split further", count, start.Format(ffsTimestampFormat), end.Format(ffsTimestampFormat), opts.MaxResults)
+		}
+
+		c.logf("Splitting %s window %s - %s (%d events)", window.term, start.Format(ffsTimestampFormat), end.Format(ffsTimestampFormat), count)
+
+		err = c.exportWindow(ctx, authData, query, window, start, mid, opts, fn)
+
+		if err != nil {
+			return err
+		}
+
+		return c.exportWindow(ctx, authData, query, window, mid.Add(time.Millisecond), end, opts, fn)
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	events := c.JsonFileEvents(ctx, authData, sliceQuery)
+	//Slices do not overlap, only events of this slice can repeat
+	seen := make(map[string]struct{})
+
+	for events.Next() {
+		event := events.Event()
+
+		if event.EventId != "" {
+			if _, ok := seen[event.EventId]; ok {
+				continue
+			}
+
+			seen[event.EventId] = struct{}{}
+		}
+
+		err = fn(event)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return events.Err()
+}
+
+/*
+countJsonFileEvents returns the TotalCount FFS reports for query, requesting a single event
+Queries over the filter limit are split and their counts summed, events matching several of them are counted more than once.
+*/
+func (c *Client) countJsonFileEvents(ctx context.Context, authData AuthData, query Query) (int64, error) {
+	query.PgSize = 1
+	query.PgToken = ""
+
+	queries, err := splitQuery(query, c.maxFilters)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+
+	for _, query := range queries {
+		page, err := c.getJsonFileEventPage(ctx, authData, query)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if page.TotalCount == nil {
+			return 0, errors.New("ffs response did not include a totalCount, cannot size time windows")
+		}
+
+		total += *page.TotalCount
+	}
+
+	return total, nil
+}
+
+// hasRangeFilter reports whether query has an ON_OR_AFTER or ON_OR_BEFORE filter on term
+func hasRangeFilter(query Query, term string) bool {
+	for _, group := range query.Groups {
+		for _, filter := range group.Filters {
+			if filter.Term == term && (filter.Operator == string(OperatorOnOrAfter) || filter.Operator == string(OperatorOnOrBefore)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// findTimeRange locates the ON_OR_AFTER and ON_OR_BEFORE filters on term, each must appear exactly once
+func findTimeRange(query Query, term string) (*timeRange, error) {
+	window := timeRange{term: term, startGroup: -1, endGroup: -1}
+
+	for g, group := range query.Groups {
+		for f, filter := range group.Filters {
+			if filter.Term != term {
+				continue
+			}
+
+			switch filter.Operator {
+			case string(OperatorOnOrAfter):
+				if window.startGroup != -1 {
+					return nil, errors.New("query has more than one ON_OR_AFTER filter on " + term)
+				}
+
+				window.startGroup, window.startIdx = g, f
+			case string(OperatorOnOrBefore):
+				if window.endGroup != -1 {
+					return nil, errors.New("query has more than one ON_OR_BEFORE filter on " + term)
+				}
+
+				window.endGroup, window.endIdx = g, f
+			}
+		}
+	}
+
+	if window.startGroup == -1 || window.endGroup == -1 {
+		return nil, errors.New("query needs both an ON_OR_AFTER and an ON_OR_BEFORE filter on " + term + " to be split into time windows")
+	}
+
+	//Bisecting a range which does not restrict the results never lowers their count
+	if query.GroupClause == string(ClauseOr) && len(query.Groups) > 1 {
+		return nil, errors.New("query joins its groups with OR, the range on " + term + " does not restrict the results and cannot be split into time windows")
+	}
+
+	for _, g := range []int{window.startGroup, window.endGroup} {
+		if group := query.Groups[g]; group.FilterClause == string(ClauseOr) && len(group.Filters) > 1 {
+			return nil, errors.New("the range on " + term + " is in a group joined with OR, it does not restrict the results and cannot be split into time windows")
+		}
+	}
+
+	var err error
+
+	window.start, err = time.Parse(time.RFC3339Nano, query.Groups[window.startGroup].Filters[window.startIdx].Value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	window.end, err = time.Parse(time.RFC3339Nano, query.Groups[window.endGroup].Filters[window.endIdx].Value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if window.end.Before(window.start) {
+		return nil, errors.New("ON_OR_BEFORE of " + term + " is before its ON_OR_AFTER")
+	}
+
+	return &window, nil
+}
+
+// apply returns a copy of query whose range filters are set to [start, end], starting from the slice's first page
+func (w *timeRange) apply(query Query, start time.Time, end time.Time) Query {
+	groups := cloneGroups(query.Groups)
+
+	groups[w.startGroup].Filters[w.startIdx].Value = start.UTC().Format(ffsTimestampFormat)
+	groups[w.endGroup].Filters[w.endIdx].Value = end.UTC().Format(ffsTimestampFormat)
+
+	query.Groups = groups
+	//A page token belongs to the query it was returned for, never to a slice
+	query.PgToken = ""
+
+	return query
+}
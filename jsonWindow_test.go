@@ -0,0 +1,186 @@
+package ffs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportWindowedJsonFileEvents(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	//20 events, one per minute, with two sharing the last timestamp
+	var events []JsonFileEvent
+
+	for i := 0; i < 20; i++ {
+		events = append(events, JsonFileEvent{EventId: strconv.Itoa(i), InsertionTimestamp: NewTimestamp(base.Add(time.Duration(i) * time.Minute))})
+	}
+
+	events = append(events, JsonFileEvent{EventId: "20", InsertionTimestamp: events[19].InsertionTimestamp})
+
+	largestExport := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query Query
+		_ = json.NewDecoder(r.Body).Decode(&query)
+
+		window, err := findTimeRange(query, "insertionTimestamp")
+
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		var matches []JsonFileEvent
+
+		for _, event := range events {
+			ts := event.InsertionTimestamp.Time
+
+			if !ts.Before(window.start) && !ts.After(window.end) {
+				matches = append(matches, event)
+			}
+		}
+
+		total := int64(len(matches))
+
+		//Page through matches, the token is the offset of the page
+		offset, _ := strconv.Atoi(query.PgToken)
+		end := offset + query.PgSize
+
+		if end > len(matches) {
+			end = len(matches)
+		}
+
+		response := JsonFileEventResponse{FileEvents: matches[offset:end], TotalCount: &total}
+
+		if end < len(matches) {
+			response.NextPgToken = strconv.Itoa(end)
+		}
+
+		if query.PgSize > 1 && len(matches) > largestExport {
+			largestExport = len(matches)
+		}
+
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	query := jsonQuery
+	query.PgSize = 2
+	query.Groups = []Group{{
+		Filters: []SearchFilter{
+			{Operator: "ON_OR_AFTER", Term: "insertionTimestamp", Value: base.Format(ffsTimestampFormat)},
+			{Operator: "ON_OR_BEFORE", Term: "insertionTimestamp", Value: base.Add(time.Hour).Format(ffsTimestampFormat)},
+		},
+		FilterClause: "AND",
+	}}
+
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL))
+
+	seen := make(map[string]int)
+
+	err := c.ExportWindowedJsonFileEvents(context.Background(), AuthData{AccessToken: "token"}, query, WindowedExportOptions{MaxResults: 3}, func(event JsonFileEvent) error {
+		seen[event.EventId]++
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seen) != len(events) {
+		t.Errorf("expected %d events, got %d", len(events), len(seen))
+	}
+
+	for id, n := range seen {
+		if n != 1 {
+			t.Errorf("event %s delivered %d times", id, n)
+		}
+	}
+
+	if largestExport > 3 {
+		t.Errorf("a slice of %d events was exported, above the cap of 3", largestExport)
+	}
+
+	//Three events on the same millisecond cannot be split under a cap of 2
+	events = append(events, JsonFileEvent{EventId: "21", InsertionTimestamp: events[19].InsertionTimestamp})
+
+	err = c.ExportWindowedJsonFileEvents(context.Background(), AuthData{AccessToken: "token"}, query, WindowedExportOptions{MaxResults: 2}, func(event JsonFileEvent) error { return nil })
+
+	if err == nil {
+		t.Error("expected an error for a window which cannot be split under the cap")
+	}
+}
+
+func TestFindTimeRangeMissingFilter(t *testing.T) {
+	_, err := findTimeRange(Query{Groups: []Group{{Filters: []SearchFilter{{Operator: "ON_OR_AFTER", Term: "eventTimestamp", Value: "2020-01-01T00:00:00.000Z"}}}}}, "eventTimestamp")
+
+	if err == nil {
+		t.Error("expected an error without an ON_OR_BEFORE filter")
+	}
+}
+
+func TestExportWindowedJsonFileEventsIgnoresPageToken(t *testing.T) {
+	var tokens []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query Query
+		_ = json.NewDecoder(r.Body).Decode(&query)
+
+		tokens = append(tokens, query.PgToken)
+
+		total := int64(1)
+		_ = json.NewEncoder(w).Encode(JsonFileEventResponse{FileEvents: []JsonFileEvent{{EventId: "a"}}, TotalCount: &total})
+	}))
+	defer server.Close()
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := NewQueryBuilder().Between(TermInsertionTimestamp, start, start.Add(time.Hour)).PageToken("stale").Build()
+
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL), WithRetryPolicy(NoRetry))
+
+	err := c.ExportWindowedJsonFileEvents(context.Background(), AuthData{AccessToken: "token"}, query, WindowedExportOptions{}, func(event JsonFileEvent) error { return nil })
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tokens) != 2 || tokens[0] != "" || tokens[1] != "" {
+		t.Errorf("unexpected page tokens %q", tokens)
+	}
+}
+
+func TestFindTimeRangeNotRestricting(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	queries := []Query{
+		NewQueryBuilder().Any(TermInsertionTimestamp.OnOrAfter(start), TermInsertionTimestamp.OnOrBefore(start.Add(time.Hour)), TermFileName.Is("a")).Build(),
+		NewQueryBuilder().Between(TermInsertionTimestamp, start, start.Add(time.Hour)).All(TermFileName.Is("a")).MatchAnyGroup().Build(),
+	}
+
+	for _, query := range queries {
+		if _, err := findTimeRange(query, "insertionTimestamp"); err == nil || !strings.Contains(err.Error(), "OR") {
+			t.Errorf("expected an OR error for %+v, got %v", query, err)
+		}
+
+		//The error for the range found is reported instead of falling back to eventTimestamp
+		c := NewClient(WithFFSURL("http://127.0.0.1:0"))
+		err := c.ExportWindowedJsonFileEvents(context.Background(), AuthData{AccessToken: "token"}, query, WindowedExportOptions{}, func(event JsonFileEvent) error { return nil })
+
+		if err == nil || !strings.Contains(err.Error(), "does not restrict") {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+
+	//A single group query with OR between its groups still restricts the results
+	query := NewQueryBuilder().Between(TermInsertionTimestamp, start, start.Add(time.Hour)).MatchAnyGroup().Build()
+
+	if _, err := findTimeRange(query, "insertionTimestamp"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,128 @@
+package ffs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Code42 OAuth API Client Auth
+
+// DefaultOAuthURL - Code42 OAuth token endpoint used for API client (client credentials) authentication
+const DefaultOAuthURL = "https://api.us.code42.com/v1/oauth"
+
+// WithOAuthURL - Set the OAuth token endpoint used for API client authentication
+func WithOAuthURL(uri string) Option {
+	return func(c *Client) {
+		c.oauthURL = uri
+	}
+}
+
+/*
+GetClientCredentialsAuthData - Function to get Authentication data for a Code42 API client (client ID + secret)
+Performs the OAuth client-credentials grant, the returned AuthData can be used with GetJsonFileEvents and GetCsvFileEvents
+*/
+func GetClientCredentialsAuthData(uri string, clientID string, clientSecret string) (*AuthData, error) {
+	return GetClientCredentialsAuthDataContext(context.Background(), uri, clientID, clientSecret)
+}
+
+// GetClientCredentialsAuthDataContext - GetClientCredentialsAuthData which can be cancelled through ctx
+func GetClientCredentialsAuthDataContext(ctx context.Context, uri string, clientID string, clientSecret string) (*AuthData, error) {
+	return NewClient(WithOAuthURL(uri)).GetClientCredentialsAuthDataContext(ctx, clientID, clientSecret)
+}
+
+// GetClientCredentialsAuthData - Get Authentication data for a Code42 API client from the client's OAuth URL
+func (c *Client) GetClientCredentialsAuthData(clientID string, clientSecret string) (*AuthData, error) {
+	return c.GetClientCredentialsAuthDataContext(context.Background(), clientID, clientSecret)
+}
+
+// GetClientCredentialsAuthDataContext - GetClientCredentialsAuthData which can be cancelled through ctx, transient failures are retried according to the client's retry policy
+func (c *Client) GetClientCredentialsAuthDataContext(ctx context.Context, clientID string, clientSecret string) (*AuthData, error) {
+	var authData *AuthData
+
+	err := c.withRetry(ctx, "OAuth Token POST", func() (err error) {
+		authData, err = c.getClientCredentialsAuthData(ctx, clientID, clientSecret)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return authData, nil
+}
+
+// getClientCredentialsAuthData makes a single client credentials grant request
+func (c *Client) getClientCredentialsAuthData(ctx context.Context, clientID string, clientSecret string) (*AuthData, error) {
+	//Build client credentials grant body
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+
+	req, err := c.newRequest(ctx, "POST", c.oauthURL, strings.NewReader(form.Encode()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	//API client ID and secret are sent as Basic Auth
+	req.SetBasicAuth(clientID, clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.do(ctx, req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	//Return err if status code != 200
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPError("OAuth Token POST", resp)
+	}
+
+	responseBytes, err := ioutil.ReadAll(resp.Body)
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return nil, err
+	}
+
+	var authData AuthData
+
+	err = json.Unmarshal(responseBytes, &authData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if authData.Error != "" {
+		return nil, errors.New("error getting oauth token: " + authData.Error)
+	}
+
+	if authData.AccessToken == "" {
+		return nil, errors.New("oauth response did not contain an access token")
+	}
+
+	//Tokens are sent as "Authorization: Bearer", any other type cannot be used with the FFS endpoints
+	if authData.TokenType != "" && !strings.EqualFold(authData.TokenType, "bearer") {
+		return nil, errors.New("unsupported oauth token type: " + authData.TokenType)
+	}
+
+	return &authData, nil
+}
+
+// ClientCredentialsTokenSource - CachingTokenSource which authenticates as a Code42 API client against the client's OAuth URL
+func (c *Client) ClientCredentialsTokenSource(clientID string, clientSecret string) *CachingTokenSource {
+	return NewCachingTokenSource(func(ctx context.Context) (*AuthData, error) {
+		return c.GetClientCredentialsAuthDataContext(ctx, clientID, clientSecret)
+	}, DefaultRefreshBefore)
+}
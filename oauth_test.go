@@ -0,0 +1,42 @@
+package ffs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetClientCredentialsAuthData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+
+		if !ok || id != "key-123" || secret != "shh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+			t.Error("expected grant_type=client_credentials")
+		}
+
+		_, _ = w.Write([]byte(`{"access_token":"api-token","token_type":"bearer","expires_in":900}`))
+	}))
+	defer server.Close()
+
+	authData, err := GetClientCredentialsAuthData(server.URL, "key-123", "shh")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if authData.AccessToken != "api-token" || authData.TokenType != "bearer" || *authData.ExpiresIn != 900 {
+		t.Errorf("unexpected auth data: %+v", authData)
+	}
+
+	_, err = GetClientCredentialsAuthData(server.URL, "key-123", "wrong")
+
+	if err == nil {
+		t.Error("expected an error for a bad secret")
+	}
+}
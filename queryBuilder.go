@@ -0,0 +1,321 @@
+package ffs
+
+import (
+	"strconv"
+	"time"
+)
+
+// FFS Query Builder
+
+// Term - A file event field a SearchFilter matches on
+type Term string
+
+// Searchable terms, the json names of the JsonFileEvent fields
+const (
+	TermActor                      Term = "actor"
+	TermCloudDriveId               Term = "cloudDriveId"
+	TermCreateTimestamp            Term = "createTimestamp"
+	TermDestinationCategory        Term = "destinationCategory"
+	TermDestinationName            Term = "destinationName"
+	TermDetectionSourceAlias       Term = "detectionSourceAlias"
+	TermDeviceUid                  Term = "deviceUid"
+	TermDeviceUserName             Term = "deviceUserName"
+	TermDirectoryId                Term = "directoryId"
+	TermDomainName                 Term = "domainName"
+	TermEmailDlpPolicyNames        Term = "emailDlpPolicyNames"
+	TermEmailFrom                  Term = "emailFrom"
+	TermEmailRecipients            Term = "emailRecipients"
+	TermEmailSender                Term = "emailSender"
+	TermEmailSubject               Term = "emailSubject"
+	TermEventId                    Term = "eventId"
+	TermEventTimestamp             Term = "eventTimestamp"
+	TermEventType                  Term = "eventType"
+	TermExposure                   Term = "exposure"
+	TermFileCategory               Term = "fileCategory"
+	TermFileCategoryByBytes        Term = "fileCategoryByBytes"
+	TermFileCategoryByExtension    Term = "fileCategoryByExtension"
+	TermFileId                     Term = "fileId"
+	TermFileName                   Term = "fileName"
+	TermFileOwner                  Term = "fileOwner"
+	TermFilePath                   Term = "filePath"
+	TermFileSize                   Term = "fileSize"
+	TermFileType                   Term = "fileType"
+	TermInsertionTimestamp         Term = "insertionTimestamp"
+	TermMd5Checksum                Term = "md5Checksum"
+	TermMimeTypeByBytes            Term = "mimeTypeByBytes"
+	TermMimeTypeByExtension        Term = "mimeTypeByExtension"
+	TermMimeTypeMismatch           Term = "mimeTypeMismatch"
+	TermModifyTimestamp            Term = "modifyTimestamp"
+	TermOperatingSystemUser        Term = "operatingSystemUser"
+	TermOsHostName                 Term = "osHostName"
+	TermOutsideActiveHours         Term = "outsideActiveHours"
+	TermPrintJobName               Term = "printJobName"
+	TermPrinterName                Term = "printerName"
+	TermPrivateIpAddresses         Term = "privateIpAddresses"
+	TermProcessName                Term = "processName"
+	TermProcessOwner               Term = "processOwner"
+	TermPublicIpAddress            Term = "publicIpAddress"
+	TermRemoteActivity             Term = "remoteActivity"
+	TermRemovableMediaBusType      Term = "removableMediaBusType"
+	TermRemovableMediaCapacity     Term = "removableMediaCapacity"
+	TermRemovableMediaMediaName    Term = "removableMediaMediaName"
+	TermRemovableMediaName         Term = "removableMediaName"
+	TermRemovableMediaPartitionId  Term = "removableMediaPartitionId"
+	TermRemovableMediaSerialNumber Term = "removableMediaSerialNumber"
+	TermRemovableMediaVendor       Term = "removableMediaVendor"
+	TermRemovableMediaVolumeName   Term = "removableMediaVolumeName"
+	TermSha256Checksum             Term = "sha256Checksum"
+	TermShared                     Term = "shared"
+	TermSharedWith                 Term = "sharedWith"
+	TermSharingTypeAdded           Term = "sharingTypeAdded"
+	TermSource                     Term = "source"
+	TermSyncDestination            Term = "syncDestination"
+	TermSyncDestinationUsername    Term = "syncDestinationUsername"
+	TermTabUrl                     Term = "tabUrl"
+	TermTrusted                    Term = "trusted"
+	TermUrl                        Term = "url"
+	TermUserUid                    Term = "userUid"
+	TermWindowTitle                Term = "windowTitle"
+)
+
+// Operator - How a SearchFilter compares its term with its value
+type Operator string
+
+const (
+	OperatorIs            Operator = "IS"
+	OperatorIsNot         Operator = "IS_NOT"
+	OperatorExists        Operator = "EXISTS"
+	OperatorDoesNotExist  Operator = "DOES_NOT_EXIST"
+	OperatorOnOrAfter     Operator = "ON_OR_AFTER"
+	OperatorOnOrBefore    Operator = "ON_OR_BEFORE"
+	OperatorWithinTheLast Operator = "WITHIN_THE_LAST"
+	OperatorGreaterThan   Operator = "GREATER_THAN"
+	OperatorLessThan      Operator = "LESS_THAN"
+)
+
+// Clause - How the filters of a group, or the groups of a query, are combined
+type Clause string
+
+const (
+	ClauseAnd Clause = "AND"
+	ClauseOr  Clause = "OR"
+)
+
+// SortDirection - Order of the results of a query
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+func (t Term) String() string     { return string(t) }
+func (o Operator) String() string { return string(o) }
+
+func (t Term) filter(operator Operator, value string) SearchFilter {
+	return SearchFilter{Operator: string(operator), Term: string(t), Value: value}
+}
+
+// Is - Match events whose term equals value, * matches any characters
+func (t Term) Is(value string) SearchFilter {
+	return t.filter(OperatorIs, value)
+}
+
+// IsNot - Match events whose term does not equal value
+func (t Term) IsNot(value string) SearchFilter {
+	return t.filter(OperatorIsNot, value)
+}
+
+// Exists - Match events which have a value for term
+func (t Term) Exists() SearchFilter {
+	return t.filter(OperatorExists, "")
+}
+
+// DoesNotExist - Match events which have no value for term
+func (t Term) DoesNotExist() SearchFilter {
+	return t.filter(OperatorDoesNotExist, "")
+}
+
+// OnOrAfter - Match events whose timestamp term is at or after at
+func (t Term) OnOrAfter(at time.Time) SearchFilter {
+	return t.filter(OperatorOnOrAfter, at.UTC().Format(ffsTimestampFormat))
+}
+
+// OnOrBefore - Match events whose timestamp term is at or before at
+func (t Term) OnOrBefore(at time.Time) SearchFilter {
+	return t.filter(OperatorOnOrBefore, at.UTC().Format(ffsTimestampFormat))
+}
+
+// WithinTheLast - Match events whose timestamp term lies within d before the time the query runs
+func (t Term) WithinTheLast(d time.Duration) SearchFilter {
+	return t.filter(OperatorWithinTheLast, isoDuration(d))
+}
+
+// GreaterThan - Match events whose numeric term is above n
+func (t Term) GreaterThan(n int64) SearchFilter {
+	return t.filter(OperatorGreaterThan, strconv.FormatInt(n, 10))
+}
+
+// LessThan - Match events whose numeric term is below n
+func (t Term) LessThan(n int64) SearchFilter {
+	return t.filter(OperatorLessThan, strconv.FormatInt(n, 10))
+}
+
+// EventTypeIs - Match events of eventType
+func EventTypeIs(eventType EventType) SearchFilter {
+	return TermEventType.Is(string(eventType))
+}
+
+// SourceIs - Match events observed by source
+func SourceIs(source Source) SearchFilter {
+	return TermSource.Is(string(source))
+}
+
+// ExposureIs - Match events with exposure
+func ExposureIs(exposure ExposureType) SearchFilter {
+	return TermExposure.Is(string(exposure))
+}
+
+// FileCategoryIs - Match events of files in category
+func FileCategoryIs(category FileCategory) SearchFilter {
+	return TermFileCategory.Is(string(category))
+}
+
+// DestinationCategoryIs - Match events with destination category
+func DestinationCategoryIs(category DestinationCategory) SearchFilter {
+	return TermDestinationCategory.Is(string(category))
+}
+
+// RemovableMediaBusTypeIs - Match events on removable media attached to busType
+func RemovableMediaBusTypeIs(busType RemovableMediaBusType) SearchFilter {
+	return TermRemovableMediaBusType.Is(string(busType))
+}
+
+/*
+isoDuration formats d as the ISO 8601 duration WITHIN_THE_LAST expects,
+whole days as P<n>D, otherwise in the largest of hours, minutes or seconds which divides d
+*/
+func isoDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return "P" + strconv.FormatInt(int64(d/(24*time.Hour)), 10) + "D"
+	case d%time.Hour == 0:
+		return "PT" + strconv.FormatInt(int64(d/time.Hour), 10) + "H"
+	case d%time.Minute == 0:
+		return "PT" + strconv.FormatInt(int64(d/time.Minute), 10) + "M"
+	default:
+		return "PT" + strconv.FormatInt(int64(d/time.Second), 10) + "S"
+	}
+}
+
+/*
+QueryBuilder - Builds a Query from typed terms and operators
+Every group added is one Group of the query, groups are combined with AND unless MatchAnyGroup is called.
+
+	query := ffs.NewQueryBuilder().
+		All(ffs.TermFileName.Is("*.xlsx"), ffs.EventTypeIs(ffs.EventTypeCreated)).
+		Between(ffs.TermInsertionTimestamp, start, end).
+		SortBy(ffs.TermInsertionTimestamp, ffs.SortAsc).
+		PageSize(1000).
+		Build()
+*/
+type QueryBuilder struct {
+	query Query
+}
+
+// NewQueryBuilder - Start an empty query, groups are combined with AND
+func NewQueryBuilder() *QueryBuilder {
+	return &QueryBuilder{query: Query{GroupClause: string(ClauseAnd)}}
+}
+
+func (b *QueryBuilder) group(clause Clause, filters []SearchFilter) *QueryBuilder {
+	b.query.Groups = append(b.query.Groups, Group{
+		Filters:      append([]SearchFilter(nil), filters...),
+		FilterClause: string(clause),
+	})
+
+	return b
+}
+
+// All - Add a group matching events which match every filter
+func (b *QueryBuilder) All(filters ...SearchFilter) *QueryBuilder {
+	return b.group(ClauseAnd, filters)
+}
+
+// Any - Add a group matching events which match at least one filter
+func (b *QueryBuilder) Any(filters ...SearchFilter) *QueryBuilder {
+	return b.group(ClauseOr, filters)
+}
+
+// Between - Add a group matching events whose timestamp term lies in [start, end]
+func (b *QueryBuilder) Between(term Term, start time.Time, end time.Time) *QueryBuilder {
+	return b.All(term.OnOrAfter(start), term.OnOrBefore(end))
+}
+
+// WithinTheLast - Add a group matching events whose timestamp term lies within d before the time the query runs
+func (b *QueryBuilder) WithinTheLast(term Term, d time.Duration) *QueryBuilder {
+	return b.All(term.WithinTheLast(d))
+}
+
+// MatchAnyGroup - Combine the groups with OR instead of AND
+func (b *QueryBuilder) MatchAnyGroup() *QueryBuilder {
+	b.query.GroupClause = string(ClauseOr)
+	return b
+}
+
+// SortBy - Sort the results by term
+func (b *QueryBuilder) SortBy(term Term, direction SortDirection) *QueryBuilder {
+	b.query.SrtKey = string(term)
+	b.query.SrtDir = string(direction)
+	return b
+}
+
+// PageSize - Request size events per page
+func (b *QueryBuilder) PageSize(size int) *QueryBuilder {
+	b.query.PgSize = size
+	return b
+}
+
+// PageNumber - Request page number (1 based) instead of paging with tokens
+func (b *QueryBuilder) PageNumber(number int) *QueryBuilder {
+	b.query.PgNum = number
+	return b
+}
+
+// PageToken - Continue from token, an empty token starts at the first page
+func (b *QueryBuilder) PageToken(token string) *QueryBuilder {
+	b.query.PgToken = token
+	return b
+}
+
+// Match - Add a group for each multi-value filter
+func (b *QueryBuilder) Match(filters ...MultiValueFilter) *QueryBuilder {
+	for _, filter := range filters {
+		group := filter.Group()
+		b.group(Clause(group.FilterClause), group.Filters)
+	}
+
+	return b
+}
+
+// Build - The Query, later changes to the builder do not affect it
+func (b *QueryBuilder) Build() Query {
+	query := b.query
+	query.Groups = cloneGroups(b.query.Groups)
+
+	return query
+}
+
+// cloneGroups copies groups and their filters
+func cloneGroups(groups []Group) []Group {
+	clone := make([]Group, len(groups))
+
+	for i, group := range groups {
+		clone[i] = Group{
+			Filters:      append([]SearchFilter(nil), group.Filters...),
+			FilterClause: group.FilterClause,
+		}
+	}
+
+	return clone
+}
@@ -0,0 +1,70 @@
+package ffs
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryBuilderMatchesHandWrittenQuery(t *testing.T) {
+	start := time.Date(2019, 8, 18, 20, 31, 48, 728000000, time.UTC)
+	end := time.Date(2019, 8, 18, 20, 32, 3, 728000000, time.UTC)
+
+	query := NewQueryBuilder().
+		All(TermFileName.Is("*"), TermInsertionTimestamp.OnOrAfter(start), TermInsertionTimestamp.OnOrBefore(end)).
+		SortBy(TermInsertionTimestamp, SortAsc).
+		PageSize(100).
+		Build()
+
+	if !reflect.DeepEqual(query, jsonQuery) {
+		t.Errorf("builder produced\n%+v\nexpected\n%+v", query, jsonQuery)
+	}
+}
+
+func TestQueryBuilderGroups(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.FixedZone("EST", -5*3600))
+	builder := NewQueryBuilder().
+		Any(EventTypeIs(EventTypeCreated), EventTypeIs(EventTypeModified)).
+		All(TermFileSize.GreaterThan(1024), TermRemovableMediaVendor.Exists(), TermShared.IsNot("true")).
+		Between(TermEventTimestamp, start, start.Add(time.Hour)).
+		WithinTheLast(TermInsertionTimestamp, 7*24*time.Hour).
+		MatchAnyGroup()
+
+	query := builder.Build()
+
+	//Later changes do not leak into built queries
+	builder.All(TermFileName.Is("*.zip"))
+
+	data, err := json.Marshal(query)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"groups":[` +
+		`{"filters":[{"operator":"IS","term":"eventType","value":"CREATED"},{"operator":"IS","term":"eventType","value":"MODIFIED"}],"filterClause":"OR"},` +
+		`{"filters":[{"operator":"GREATER_THAN","term":"fileSize","value":"1024"},{"operator":"EXISTS","term":"removableMediaVendor","value":""},{"operator":"IS_NOT","term":"shared","value":"true"}],"filterClause":"AND"},` +
+		`{"filters":[{"operator":"ON_OR_AFTER","term":"eventTimestamp","value":"2020-01-01T05:00:00.000Z"},{"operator":"ON_OR_BEFORE","term":"eventTimestamp","value":"2020-01-01T06:00:00.000Z"}],"filterClause":"AND"},` +
+		`{"filters":[{"operator":"WITHIN_THE_LAST","term":"insertionTimestamp","value":"P7D"}],"filterClause":"AND"}` +
+		`],"groupClause":"OR","pgToken":""}`
+
+	if string(data) != want {
+		t.Errorf("unexpected query:\n%s\n%s", data, want)
+	}
+}
+
+func TestIsoDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		24 * time.Hour:   "P1D",
+		90 * time.Minute: "PT90M",
+		2 * time.Hour:    "PT2H",
+		45 * time.Second: "PT45S",
+	}
+
+	for d, want := range tests {
+		if got := isoDuration(d); got != want {
+			t.Errorf("%s: got %s, want %s", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,592 @@
+package ffs
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FFS Query Language
+
+/*
+The query language writes a Query as text:
+
+	eventType IN (CREATED, MODIFIED) AND fileName IS "*.xlsx" AND insertionTimestamp WITHIN_THE_LAST P7D
+
+A filter is a term, an operator and (except for EXISTS and DOES_NOT_EXIST) a value. Values are bare words or
+double quoted strings with Go escapes. Each filter, or each parenthesized list of filters joined with AND or OR,
+is one group of the query, and groups are joined with AND or OR. term IN (a, b), or IS_ANY, is a group of IS filters joined
+with OR and term NOT_IN (a, b) a group of IS_NOT filters joined with AND. An optional SORT BY term [ASC|DESC]
+and PAGE SIZE n follow the groups. Keywords and operators are case insensitive, terms are not.
+*/
+
+// QuerySyntaxError - Returned by ParseQuery, Offset is the byte offset of the problem, Line and Column count from 1
+type QuerySyntaxError struct {
+	Offset int
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *QuerySyntaxError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ", column " + strconv.Itoa(e.Column) + ": " + e.Msg
+}
+
+type queryTokenKind int
+
+const (
+	tokenEOF queryTokenKind = iota
+	tokenWord
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type queryToken struct {
+	kind  queryTokenKind
+	text  string
+	value string
+	pos   int
+}
+
+// bareWordPattern matches values which are printed without quotes
+var bareWordPattern = regexp.MustCompile(`^[A-Za-z0-9_.*:/@+\-]+$`)
+
+// queryKeywords cannot be printed as bare values
+var queryKeywords = map[string]bool{"AND": true, "OR": true, "SORT": true, "BY": true, "PAGE": true, "SIZE": true, "ASC": true, "DESC": true}
+
+// operatorIn is the language's name of IS_ANY
+const operatorIn = "IN"
+
+type queryParser struct {
+	input  string
+	tokens []queryToken
+	next   int
+}
+
+// ParseQuery - Compile query language text into a Query, see the syntax above
+func ParseQuery(text string) (Query, error) {
+	p := &queryParser{input: text}
+
+	err := p.lex()
+
+	if err != nil {
+		return Query{}, err
+	}
+
+	return p.parseQuery()
+}
+
+func (p *queryParser) errorAt(pos int, msg string) *QuerySyntaxError {
+	line, column := 1, 1
+
+	for _, r := range p.input[:pos] {
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+
+	return &QuerySyntaxError{Offset: pos, Line: line, Column: column, Msg: msg}
+}
+
+func (p *queryParser) lex() error {
+	for i := 0; i < len(p.input); {
+		c := p.input[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, queryToken{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, queryToken{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			p.tokens = append(p.tokens, queryToken{kind: tokenComma, text: ",", pos: i})
+			i++
+		case c == '"':
+			end := i + 1
+
+			for end < len(p.input) && p.input[end] != '"' {
+				if p.input[end] == '\\' {
+					end++
+				}
+
+				end++
+			}
+
+			if end >= len(p.input) {
+				return p.errorAt(i, "unterminated string")
+			}
+
+			value, err := strconv.Unquote(p.input[i : end+1])
+
+			if err != nil {
+				return p.errorAt(i, "invalid string "+p.input[i:end+1])
+			}
+
+			p.tokens = append(p.tokens, queryToken{kind: tokenString, text: p.input[i : end+1], value: value, pos: i})
+			i = end + 1
+		default:
+			end := i
+
+			for end < len(p.input) && !strings.ContainsRune(" \t\n\r(),\"", rune(p.input[end])) {
+				end++
+			}
+
+			p.tokens = append(p.tokens, queryToken{kind: tokenWord, text: p.input[i:end], value: p.input[i:end], pos: i})
+			i = end
+		}
+	}
+
+	p.tokens = append(p.tokens, queryToken{kind: tokenEOF, text: "end of query", pos: len(p.input)})
+
+	return nil
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.next]
+}
+
+func (p *queryParser) take() queryToken {
+	token := p.tokens[p.next]
+
+	if token.kind != tokenEOF {
+		p.next++
+	}
+
+	return token
+}
+
+// keyword reports whether the next token is the keyword word, consuming it if so
+func (p *queryParser) keyword(word string) bool {
+	token := p.peek()
+
+	if token.kind == tokenWord && strings.EqualFold(token.text, word) {
+		p.next++
+		return true
+	}
+
+	return false
+}
+
+// clause consumes an AND or OR
+func (p *queryParser) clause() (Clause, queryToken, bool) {
+	token := p.peek()
+
+	for _, clause := range []Clause{ClauseAnd, ClauseOr} {
+		if p.keyword(string(clause)) {
+			return clause, token, true
+		}
+	}
+
+	return "", token, false
+}
+
+func (p *queryParser) parseQuery() (Query, error) {
+	query := Query{GroupClause: string(ClauseAnd)}
+
+	if p.peek().kind == tokenEOF {
+		return Query{}, p.errorAt(0, "empty query")
+	}
+
+	var groupClause Clause
+
+	for {
+		group, err := p.parseGroup()
+
+		if err != nil {
+			return Query{}, err
+		}
+
+		query.Groups = append(query.Groups, group)
+
+		clause, token, ok := p.clause()
+
+		if !ok {
+			break
+		}
+
+		if groupClause != "" && clause != groupClause {
+			return Query{}, p.errorAt(token.pos, "cannot mix AND and OR between groups, put the filters in parentheses")
+		}
+
+		groupClause = clause
+	}
+
+	if groupClause != "" {
+		query.GroupClause = string(groupClause)
+	}
+
+	if p.keyword("SORT") {
+		if !p.keyword("BY") {
+			return Query{}, p.errorAt(p.peek().pos, "expected BY after SORT")
+		}
+
+		term, err := p.parseTerm()
+
+		if err != nil {
+			return Query{}, err
+		}
+
+		query.SrtKey = string(term)
+		query.SrtDir = string(SortAsc)
+
+		if p.keyword("DESC") {
+			query.SrtDir = string(SortDesc)
+		} else {
+			p.keyword("ASC")
+		}
+	}
+
+	if p.keyword("PAGE") {
+		if !p.keyword("SIZE") {
+			return Query{}, p.errorAt(p.peek().pos, "expected SIZE after PAGE")
+		}
+
+		token := p.take()
+		size, err := strconv.Atoi(token.value)
+
+		if token.kind != tokenWord || err != nil || size <= 0 {
+			return Query{}, p.errorAt(token.pos, "expected a page size, found "+token.text)
+		}
+
+		query.PgSize = size
+	}
+
+	if token := p.peek(); token.kind != tokenEOF {
+		return Query{}, p.errorAt(token.pos, "expected AND, OR, SORT BY or PAGE SIZE, found "+token.text)
+	}
+
+	return query, nil
+}
+
+// parseGroup parses a single filter or a parenthesized list of filters
+func (p *queryParser) parseGroup() (Group, error) {
+	open := p.peek()
+
+	if open.kind != tokenLParen {
+		filters, clause, err := p.parseFilter()
+
+		if err != nil {
+			return Group{}, err
+		}
+
+		if clause == "" {
+			clause = ClauseAnd
+		}
+
+		return Group{Filters: filters, FilterClause: string(clause)}, nil
+	}
+
+	p.take()
+
+	var group Group
+	var groupClause Clause
+
+	//Lists must be joined with the clause they expand to
+	type list struct {
+		clause Clause
+		pos    int
+	}
+
+	var lists []list
+
+	for {
+		pos := p.peek().pos
+		filters, clause, err := p.parseFilter()
+
+		if err != nil {
+			return Group{}, err
+		}
+
+		if clause != "" {
+			lists = append(lists, list{clause: clause, pos: pos})
+		}
+
+		group.Filters = append(group.Filters, filters...)
+
+		clause, token, ok := p.clause()
+
+		if !ok {
+			break
+		}
+
+		if groupClause != "" && clause != groupClause {
+			return Group{}, p.errorAt(token.pos, "cannot mix AND and OR inside parentheses")
+		}
+
+		groupClause = clause
+	}
+
+	if end := p.take(); end.kind != tokenRParen {
+		return Group{}, p.errorAt(end.pos, "expected ) to close the group opened at column "+strconv.Itoa(p.errorAt(open.pos, "").Column)+", found "+end.text)
+	}
+
+	for _, l := range lists {
+		if groupClause == "" {
+			groupClause = l.clause
+		}
+
+		if l.clause != groupClause {
+			return Group{}, p.errorAt(l.pos, "a list filter expands to "+string(l.clause)+" and cannot be joined with "+string(groupClause))
+		}
+	}
+
+	if groupClause == "" {
+		groupClause = ClauseAnd
+	}
+
+	group.FilterClause = string(groupClause)
+
+	return group, nil
+}
+
+func (p *queryParser) parseTerm() (Term, error) {
+	token := p.take()
+
+	if token.kind != tokenWord || queryKeywords[strings.ToUpper(token.text)] {
+		return "", p.errorAt(token.pos, "expected a term, found "+token.text)
+	}
+
+	term := Term(token.text)
+
+	if !term.IsKnown() {
+		return "", p.errorAt(token.pos, "unknown term "+token.text)
+	}
+
+	return term, nil
+}
+
+// parseFilter parses one filter, lists return all their filters and the clause joining them
+func (p *queryParser) parseFilter() ([]SearchFilter, Clause, error) {
+	term, err := p.parseTerm()
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := p.take()
+
+	if token.kind != tokenWord {
+		return nil, "", p.errorAt(token.pos, "expected an operator after "+string(term)+", found "+token.text)
+	}
+
+	operator := strings.ToUpper(token.text)
+
+	//IS_ANY is accepted for IN and NOT IN for NOT_IN
+	if operator == string(OperatorIsAny) {
+		operator = operatorIn
+	} else if operator == "NOT" && p.keyword(operatorIn) {
+		operator = string(OperatorNotIn)
+	}
+
+	switch operator {
+	case operatorIn, string(OperatorNotIn):
+		values, err := p.parseList()
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		filter := MultiValueFilter{Term: term, Operator: OperatorIsAny}
+
+		if operator == string(OperatorNotIn) {
+			filter.Operator = OperatorNotIn
+		}
+
+		for _, value := range values {
+			filter.Values = append(filter.Values, value.value)
+		}
+
+		group := filter.Group()
+
+		for i, value := range values {
+			if problemType, description := validateFilter(group.Filters[i]); problemType != "" {
+				return nil, "", p.errorAt(value.pos, description)
+			}
+		}
+
+		return group.Filters, Clause(group.FilterClause), nil
+	case string(OperatorExists), string(OperatorDoesNotExist):
+		return []SearchFilter{term.filter(Operator(operator), "")}, "", nil
+	}
+
+	filter := term.filter(Operator(operator), "")
+
+	//Operator problems are reported at the operator, value problems at the value
+	if problemType, description := validateFilter(filter); problemType == ProblemUnknownOperator || problemType == ProblemInvalidOperator {
+		return nil, "", p.errorAt(token.pos, description)
+	}
+
+	value := p.take()
+
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, "", p.errorAt(value.pos, "expected a value after "+operator+", found "+value.text)
+	}
+
+	filter.Value = value.value
+
+	if _, description := validateFilter(filter); description != "" {
+		return nil, "", p.errorAt(value.pos, description)
+	}
+
+	return []SearchFilter{filter}, "", nil
+}
+
+// parseList parses (value, value, ...)
+func (p *queryParser) parseList() ([]queryToken, error) {
+	if open := p.take(); open.kind != tokenLParen {
+		return nil, p.errorAt(open.pos, "expected ( to start a list of values, found "+open.text)
+	}
+
+	var values []queryToken
+
+	for {
+		value := p.take()
+
+		if value.kind != tokenWord && value.kind != tokenString {
+			return nil, p.errorAt(value.pos, "expected a value, found "+value.text)
+		}
+
+		values = append(values, value)
+
+		separator := p.take()
+
+		if separator.kind == tokenRParen {
+			return values, nil
+		}
+
+		if separator.kind != tokenComma {
+			return nil, p.errorAt(separator.pos, "expected , or ) in the list of values, found "+separator.text)
+		}
+	}
+}
+
+/*
+FormatQuery - Render query in the query language
+An OR group of IS filters on one term is written as IN, an AND group of IS_NOT filters on one term as NOT_IN.
+Page numbers and tokens are not part of the language and are left out.
+*/
+func FormatQuery(query Query) string {
+	var b strings.Builder
+
+	groupClause := query.GroupClause
+
+	if groupClause == "" {
+		groupClause = string(ClauseAnd)
+	}
+
+	for i, group := range query.Groups {
+		if i > 0 {
+			b.WriteString(" " + groupClause + " ")
+		}
+
+		formatGroup(&b, group)
+	}
+
+	if query.SrtKey != "" {
+		b.WriteString(" SORT BY " + query.SrtKey)
+
+		if strings.EqualFold(query.SrtDir, string(SortDesc)) {
+			b.WriteString(" DESC")
+		} else {
+			b.WriteString(" ASC")
+		}
+	}
+
+	if query.PgSize > 0 {
+		b.WriteString(" PAGE SIZE " + strconv.Itoa(query.PgSize))
+	}
+
+	return strings.TrimPrefix(b.String(), " ")
+}
+
+func formatGroup(b *strings.Builder, group Group) {
+	filterClause := group.FilterClause
+
+	if filterClause == "" {
+		filterClause = string(ClauseAnd)
+	}
+
+	//An empty group has no syntax of its own, it is printed as () which ParseQuery rejects as Validate does
+	if len(group.Filters) == 0 {
+		b.WriteString("()")
+		return
+	}
+
+	if len(group.Filters) == 1 {
+		formatFilter(b, group.Filters[0])
+		return
+	}
+
+	if list := listOperator(group); list != "" {
+		b.WriteString(group.Filters[0].Term + " " + list + " (")
+
+		for i, filter := range group.Filters {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+
+			b.WriteString(formatValue(filter.Value))
+		}
+
+		b.WriteString(")")
+		return
+	}
+
+	b.WriteString("(")
+
+	for i, filter := range group.Filters {
+		if i > 0 {
+			b.WriteString(" " + filterClause + " ")
+		}
+
+		formatFilter(b, filter)
+	}
+
+	b.WriteString(")")
+}
+
+// listOperator returns IN or NOT_IN if group can be written as a list, "" otherwise
+func listOperator(group Group) string {
+	if len(group.Filters) == 0 {
+		return ""
+	}
+
+	operator, list := OperatorIs, operatorIn
+
+	if group.FilterClause == string(ClauseAnd) || group.FilterClause == "" {
+		operator, list = OperatorIsNot, string(OperatorNotIn)
+	}
+
+	for _, filter := range group.Filters {
+		if filter.Term != group.Filters[0].Term || filter.Operator != string(operator) {
+			return ""
+		}
+	}
+
+	return list
+}
+
+func formatFilter(b *strings.Builder, filter SearchFilter) {
+	b.WriteString(filter.Term + " " + filter.Operator)
+
+	if filter.Operator != string(OperatorExists) && filter.Operator != string(OperatorDoesNotExist) {
+		b.WriteString(" " + formatValue(filter.Value))
+	}
+}
+
+// formatValue writes value bare when it reads back unchanged, quoted otherwise
+func formatValue(value string) string {
+	if bareWordPattern.MatchString(value) && !queryKeywords[strings.ToUpper(value)] {
+		return value
+	}
+
+	return strconv.Quote(value)
+}
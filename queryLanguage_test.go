@@ -0,0 +1,126 @@
+package ffs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	query, err := ParseQuery(`eventType IN (CREATED, MODIFIED) AND fileName IS "*.xlsx" AND insertionTimestamp WITHIN_THE_LAST P7D`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := NewQueryBuilder().
+		Any(EventTypeIs(EventTypeCreated), EventTypeIs(EventTypeModified)).
+		All(TermFileName.Is("*.xlsx")).
+		All(TermInsertionTimestamp.WithinTheLast(7 * 24 * time.Hour)).
+		Build()
+
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("parsed\n%+v\nexpected\n%+v", query, want)
+	}
+}
+
+func TestParseQueryMatchesHandWrittenQuery(t *testing.T) {
+	query, err := ParseQuery(`(fileName IS * and insertionTimestamp ON_OR_AFTER 2019-08-18T20:31:48.728Z AND insertionTimestamp ON_OR_BEFORE 2019-08-18T20:32:03.728Z)
+sort by insertionTimestamp page size 100`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(query, jsonQuery) {
+		t.Errorf("parsed\n%+v\nexpected\n%+v", query, jsonQuery)
+	}
+}
+
+func TestFormatQueryRoundTrip(t *testing.T) {
+	queries := []string{
+		`eventType IN (CREATED, MODIFIED) AND fileName IS *.xlsx AND insertionTimestamp WITHIN_THE_LAST P7D`,
+		`(fileSize GREATER_THAN 1024 OR removableMediaVendor EXISTS) OR fileName NOT_IN ("a b.txt", "AND", "quote\"d") SORT BY eventTimestamp DESC PAGE SIZE 500`,
+		`(shared IS true AND trusted DOES_NOT_EXIST)`,
+	}
+
+	for _, text := range queries {
+		query, err := ParseQuery(text)
+
+		if err != nil {
+			t.Errorf("%s: %v", text, err)
+			continue
+		}
+
+		if formatted := FormatQuery(query); formatted != text {
+			t.Errorf("formatted as\n%s\nexpected\n%s", formatted, text)
+		}
+	}
+
+	//Queries from other sources format to text which parses back to the same query
+	built := NewQueryBuilder().
+		All(TermFileName.Is("My Documents"), TermFileSize.LessThan(10)).
+		Any(TermFileName.Is("x"), TermShared.Is("true")).
+		Build()
+
+	query, err := ParseQuery(FormatQuery(built))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(query, built) {
+		t.Errorf("round trip produced\n%+v\nexpected\n%+v", query, built)
+	}
+}
+
+func TestFormatQueryEmptyGroups(t *testing.T) {
+	query := Query{Groups: []Group{{}, {FilterClause: "OR"}, {Filters: []SearchFilter{TermFileName.Is("a")}}}}
+
+	if formatted := FormatQuery(query); formatted != "() AND () AND fileName IS a" {
+		t.Errorf("unexpected format %s", formatted)
+	}
+
+	if formatted := FormatQuery(Query{}); formatted != "" {
+		t.Errorf("unexpected format %s for an empty query", formatted)
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		text   string
+		line   int
+		column int
+	}{
+		{``, 1, 1},
+		{`filename IS a`, 1, 1},
+		{`fileName STARTS_WITH a`, 1, 10},
+		{`fileName ON_OR_AFTER 2020-01-01T00:00:00.000Z`, 1, 10},
+		{`fileSize GREATER_THAN 1kb`, 1, 23},
+		{`fileName IS`, 1, 12},
+		{`fileName IS "abc`, 1, 13},
+		{"fileName IS a AND\n  fileSize IS 1 OR fileSize IS 2", 2, 17},
+		{`(fileName IS a AND fileName IS b`, 1, 33},
+		{`(fileName IS a AND eventType IN (CREATED))`, 1, 20},
+		{`eventType IN (CREATED MODIFIED)`, 1, 23},
+		{`fileName IS a SORT insertionTimestamp`, 1, 20},
+		{`fileName IS a PAGE SIZE many`, 1, 25},
+		{`fileName IS a fileName IS b`, 1, 15},
+	}
+
+	for _, test := range tests {
+		_, err := ParseQuery(test.text)
+
+		var syntaxErr *QuerySyntaxError
+
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%q: expected a *QuerySyntaxError, got %v", test.text, err)
+			continue
+		}
+
+		if syntaxErr.Line != test.line || syntaxErr.Column != test.column {
+			t.Errorf("%q: error at line %d, column %d, expected line %d, column %d: %v", test.text, syntaxErr.Line, syntaxErr.Column, test.line, test.column, err)
+		}
+	}
+}
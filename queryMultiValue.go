@@ -0,0 +1,137 @@
+package ffs
+
+import (
+	"fmt"
+)
+
+// FFS Multi-Value Filters
+
+// MaxFiltersPerQuery - Default limit on the filters of a single query, larger queries are split into several
+const MaxFiltersPerQuery = 1024
+
+// Multi-value operators, sent as one IS filter per value joined with OR and one IS_NOT filter per value joined with AND
+const (
+	OperatorIsAny Operator = "IS_ANY"
+	OperatorNotIn Operator = "NOT_IN"
+)
+
+/*
+MultiValueFilter - Matches a term against a list of values
+FFS filters hold a single value, so the filter is sent as a group with one filter per value, see Group.
+*/
+type MultiValueFilter struct {
+	Term     Term
+	Operator Operator
+	Values   []string
+}
+
+// IsAny - Match events whose term equals any of values
+func (t Term) IsAny(values ...string) MultiValueFilter {
+	return MultiValueFilter{Term: t, Operator: OperatorIsAny, Values: append([]string(nil), values...)}
+}
+
+// NotIn - Match events whose term equals none of values
+func (t Term) NotIn(values ...string) MultiValueFilter {
+	return MultiValueFilter{Term: t, Operator: OperatorNotIn, Values: append([]string(nil), values...)}
+}
+
+// Group - The group sent for f, IS filters joined with OR for IS_ANY and IS_NOT filters joined with AND for NOT_IN
+func (f MultiValueFilter) Group() Group {
+	operator, clause := OperatorIs, ClauseOr
+
+	if f.Operator == OperatorNotIn {
+		operator, clause = OperatorIsNot, ClauseAnd
+	}
+
+	group := Group{Filters: make([]SearchFilter, len(f.Values)), FilterClause: string(clause)}
+
+	for i, value := range f.Values {
+		group.Filters[i] = f.Term.filter(operator, value)
+	}
+
+	return group
+}
+
+/*
+WithMaxFiltersPerQuery - Split queries holding more than max filters, defaults to MaxFiltersPerQuery
+Zero or less sends every query as it is.
+*/
+func WithMaxFiltersPerQuery(max int) Option {
+	return func(c *Client) {
+		c.maxFilters = max
+	}
+}
+
+func filterCount(query Query) int {
+	count := 0
+
+	for _, group := range query.Groups {
+		count += len(group.Filters)
+	}
+
+	return count
+}
+
+/*
+splitQuery returns queries which together match the events of query, each holding at most max filters
+Queries over the limit with a page token fail, the token cannot be applied to the split queries.
+The largest OR group is split into chunks, each chunk replacing the group in a copy of the query. This holds
+for both group clauses as OR distributes over AND, so AND groups, such as NOT_IN lists, cannot be split.
+*/
+func splitQuery(query Query, max int) ([]Query, error) {
+	count := filterCount(query)
+
+	if max <= 0 || count <= max {
+		return []Query{query}, nil
+	}
+
+	//A page token belongs to the query it was returned for, not to the queries it is split into
+	if query.PgToken != "" {
+		return nil, fmt.Errorf("%w: query has %d filters, more than the %d allowed per query, and a page token, split queries cannot be resumed", ErrTooManyFilters, count, max)
+	}
+
+	largest := -1
+
+	for i, group := range query.Groups {
+		if group.FilterClause == string(ClauseOr) && len(group.Filters) > 1 && (largest == -1 || len(group.Filters) > len(query.Groups[largest].Filters)) {
+			largest = i
+		}
+	}
+
+	if largest == -1 {
+		return nil, fmt.Errorf("%w: query has %d filters, more than the %d allowed per query, and no OR group which could be split", ErrTooManyFilters, count, max)
+	}
+
+	filters := query.Groups[largest].Filters
+
+	//Fill each chunk up to the limit, or halve the group if the other groups alone exceed it
+	size := max - (count - len(filters))
+
+	if size < 1 {
+		size = (len(filters) + 1) / 2
+	}
+
+	var queries []Query
+
+	for start := 0; start < len(filters); start += size {
+		end := start + size
+
+		if end > len(filters) {
+			end = len(filters)
+		}
+
+		chunk := query
+		chunk.Groups = cloneGroups(query.Groups)
+		chunk.Groups[largest].Filters = chunk.Groups[largest].Filters[start:end]
+
+		split, err := splitQuery(chunk, max)
+
+		if err != nil {
+			return nil, err
+		}
+
+		queries = append(queries, split...)
+	}
+
+	return queries, nil
+}
@@ -0,0 +1,243 @@
+package ffs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMultiValueFilterGroup(t *testing.T) {
+	query := NewQueryBuilder().
+		Match(TermDeviceUserName.IsAny("a", "b"), TermFileName.NotIn("x.tmp")).
+		Build()
+
+	want := NewQueryBuilder().
+		Any(TermDeviceUserName.Is("a"), TermDeviceUserName.Is("b")).
+		All(TermFileName.IsNot("x.tmp")).
+		Build()
+
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("unexpected query\n%+v\nexpected\n%+v", query, want)
+	}
+
+	parsed, err := ParseQuery(`deviceUserName IS_ANY (a, b) AND fileName NOT IN ("x.tmp")`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(parsed, want) {
+		t.Errorf("unexpected parsed query\n%+v\nexpected\n%+v", parsed, want)
+	}
+}
+
+func numberedValues(n int) []string {
+	var values []string
+
+	for i := 0; i < n; i++ {
+		values = append(values, strconv.Itoa(i))
+	}
+
+	return values
+}
+
+func TestSplitQuery(t *testing.T) {
+	query := NewQueryBuilder().
+		All(TermFileName.Is("*.xlsx"), TermShared.Is("true")).
+		Match(TermDeviceUserName.IsAny(numberedValues(25)...)).
+		Build()
+
+	queries, err := splitQuery(query, 10)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sizes []int
+	var users []string
+
+	for _, q := range queries {
+		sizes = append(sizes, filterCount(q))
+
+		if !reflect.DeepEqual(q.Groups[0], query.Groups[0]) {
+			t.Errorf("other groups changed: %+v", q.Groups[0])
+		}
+
+		for _, filter := range q.Groups[1].Filters {
+			users = append(users, filter.Value)
+		}
+	}
+
+	if !reflect.DeepEqual(sizes, []int{10, 10, 10, 3}) {
+		t.Errorf("unexpected query sizes %v", sizes)
+	}
+
+	if !reflect.DeepEqual(users, numberedValues(25)) {
+		t.Errorf("values lost or repeated: %v", users)
+	}
+
+	if filterCount(query) != 27 {
+		t.Errorf("the original query was modified")
+	}
+
+	//Two lists which together exceed the limit
+	query = NewQueryBuilder().Match(TermDeviceUserName.IsAny(numberedValues(6)...), TermFileName.IsAny(numberedValues(6)...)).Build()
+	queries, err = splitQuery(query, 5)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	combinations := 0
+
+	for _, q := range queries {
+		if filterCount(q) > 5 {
+			t.Errorf("query with %d filters", filterCount(q))
+		}
+
+		combinations += len(q.Groups[0].Filters) * len(q.Groups[1].Filters)
+	}
+
+	if combinations != 36 {
+		t.Errorf("queries cover %d of 36 combinations", combinations)
+	}
+
+	if _, err = splitQuery(NewQueryBuilder().Match(TermFileName.NotIn(numberedValues(11)...)).Build(), 10); err == nil {
+		t.Error("expected an error for a NOT_IN list over the limit")
+	}
+
+	if queries, _ = splitQuery(query, 0); len(queries) != 1 {
+		t.Error("a limit of 0 should not split")
+	}
+}
+
+func TestJsonFileEventsSplitQuery(t *testing.T) {
+	requests := 0
+
+	//Every user has one event, user 0 shares event "shared" with every other user
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		var query Query
+
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			t.Error(err)
+		}
+
+		if count := filterCount(query); count > 4 {
+			t.Errorf("query with %d filters sent", count)
+		}
+
+		var page JsonFileEventResponse
+
+		for _, filter := range query.Groups[0].Filters {
+			page.FileEvents = append(page.FileEvents, JsonFileEvent{EventId: "event" + filter.Value}, JsonFileEvent{EventId: "shared"})
+		}
+
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL), WithRetryPolicy(NoRetry), WithMaxFiltersPerQuery(4))
+	query := NewQueryBuilder().Match(TermDeviceUserName.IsAny(numberedValues(10)...)).Build()
+
+	events, _, err := c.GetJsonFileEventsContext(context.Background(), AuthData{AccessToken: "token"}, query, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+
+	for _, event := range *events {
+		ids = append(ids, event.EventId)
+	}
+
+	sort.Strings(ids)
+
+	if len(ids) != 11 || ids[10] != "shared" || requests != 3 {
+		t.Errorf("unexpected events %v from %d requests", ids, requests)
+	}
+}
+
+func TestQueriesOverTheFilterLimit(t *testing.T) {
+	requests := 0
+
+	//Every user has one event, the total count is the number of users queried
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		var query Query
+
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			t.Error(err)
+		}
+
+		if count := filterCount(query); count > 4 {
+			t.Errorf("query with %d filters sent", count)
+		}
+
+		var page JsonFileEventResponse
+
+		for _, filter := range query.Groups[0].Filters {
+			page.FileEvents = append(page.FileEvents, JsonFileEvent{EventId: "event" + filter.Value})
+		}
+
+		total := int64(len(page.FileEvents))
+		page.TotalCount = &total
+
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL), WithRetryPolicy(NoRetry), WithMaxFiltersPerQuery(4))
+	authData := AuthData{AccessToken: "token"}
+	query := NewQueryBuilder().Match(TermDeviceUserName.IsAny(numberedValues(6)...)).Build()
+
+	//A page token cannot be applied to the split queries
+	if _, _, err := c.GetJsonFileEventsContext(context.Background(), authData, query, "token"); !errors.Is(err, ErrTooManyFilters) {
+		t.Errorf("expected ErrTooManyFilters for a page token, got %v", err)
+	}
+
+	//Paths which cannot split fail before sending anything
+	if _, err := c.CsvFileEvents(context.Background(), authData, query); !errors.Is(err, ErrTooManyFilters) {
+		t.Errorf("expected ErrTooManyFilters from CsvFileEvents, got %v", err)
+	}
+
+	if _, err := c.ExportJsonFileEvents(context.Background(), authData, query, ExportOptions{}); !errors.Is(err, ErrTooManyFilters) {
+		t.Errorf("expected ErrTooManyFilters from ExportJsonFileEvents, got %v", err)
+	}
+
+	if requests != 0 {
+		t.Fatalf("%d requests sent for queries over the limit", requests)
+	}
+
+	//Windowed exports count and export the split queries
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	query = NewQueryBuilder().
+		Match(TermDeviceUserName.IsAny(numberedValues(6)...)).
+		Between(TermInsertionTimestamp, start, start.Add(time.Hour)).
+		Build()
+
+	var ids []string
+
+	err := c.ExportWindowedJsonFileEvents(context.Background(), authData, query, WindowedExportOptions{}, func(event JsonFileEvent) error {
+		ids = append(ids, event.EventId)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 6 {
+		t.Errorf("unexpected events %v", ids)
+	}
+}
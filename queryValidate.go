@@ -0,0 +1,196 @@
+package ffs
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FFS Query Validation
+
+// MaxPageSize - Largest pgSize FFS accepts
+const MaxPageSize = 10000
+
+// QueryProblem types reported by Query.Validate
+const (
+	ProblemNoGroups          = "NO_GROUPS"
+	ProblemEmptyGroup        = "EMPTY_GROUP"
+	ProblemUnknownTerm       = "UNKNOWN_TERM"
+	ProblemUnknownOperator   = "UNKNOWN_OPERATOR"
+	ProblemInvalidOperator   = "INVALID_OPERATOR_FOR_TERM"
+	ProblemInvalidValue      = "INVALID_VALUE"
+	ProblemInvalidClause     = "INVALID_CLAUSE"
+	ProblemInvalidPageSize   = "INVALID_PAGE_SIZE"
+	ProblemInvalidPageNumber = "INVALID_PAGE_NUMBER"
+	ProblemInvalidSortKey    = "INVALID_SORT_KEY"
+	ProblemInvalidSortDir    = "INVALID_SORT_DIRECTION"
+)
+
+// termKind decides which operators and values a term accepts
+type termKind int
+
+const (
+	stringTerm termKind = iota
+	timestampTerm
+	numericTerm
+	booleanTerm
+)
+
+var termKinds = map[Term]termKind{
+	TermActor: stringTerm, TermCloudDriveId: stringTerm, TermCreateTimestamp: timestampTerm,
+	TermDestinationCategory: stringTerm, TermDestinationName: stringTerm, TermDetectionSourceAlias: stringTerm,
+	TermDeviceUid: stringTerm, TermDeviceUserName: stringTerm, TermDirectoryId: stringTerm, TermDomainName: stringTerm,
+	TermEmailDlpPolicyNames: stringTerm, TermEmailFrom: stringTerm, TermEmailRecipients: stringTerm,
+	TermEmailSender: stringTerm, TermEmailSubject: stringTerm, TermEventId: stringTerm,
+	TermEventTimestamp: timestampTerm, TermEventType: stringTerm, TermExposure: stringTerm,
+	TermFileCategory: stringTerm, TermFileCategoryByBytes: stringTerm, TermFileCategoryByExtension: stringTerm,
+	TermFileId: stringTerm, TermFileName: stringTerm, TermFileOwner: stringTerm, TermFilePath: stringTerm,
+	TermFileSize: numericTerm, TermFileType: stringTerm, TermInsertionTimestamp: timestampTerm,
+	TermMd5Checksum: stringTerm, TermMimeTypeByBytes: stringTerm, TermMimeTypeByExtension: stringTerm,
+	TermMimeTypeMismatch: booleanTerm, TermModifyTimestamp: timestampTerm, TermOperatingSystemUser: stringTerm,
+	TermOsHostName: stringTerm, TermOutsideActiveHours: booleanTerm, TermPrintJobName: stringTerm,
+	TermPrinterName: stringTerm, TermPrivateIpAddresses: stringTerm, TermProcessName: stringTerm,
+	TermProcessOwner: stringTerm, TermPublicIpAddress: stringTerm, TermRemoteActivity: stringTerm,
+	TermRemovableMediaBusType: stringTerm, TermRemovableMediaCapacity: numericTerm,
+	TermRemovableMediaMediaName: stringTerm, TermRemovableMediaName: stringTerm,
+	TermRemovableMediaPartitionId: stringTerm, TermRemovableMediaSerialNumber: stringTerm,
+	TermRemovableMediaVendor: stringTerm, TermRemovableMediaVolumeName: stringTerm,
+	TermSha256Checksum: stringTerm, TermShared: booleanTerm, TermSharedWith: stringTerm,
+	TermSharingTypeAdded: stringTerm, TermSource: stringTerm, TermSyncDestination: stringTerm,
+	TermSyncDestinationUsername: stringTerm, TermTabUrl: stringTerm, TermTrusted: booleanTerm,
+	TermUrl: stringTerm, TermUserUid: stringTerm, TermWindowTitle: stringTerm,
+}
+
+// isoDurationPattern matches the ISO 8601 durations WITHIN_THE_LAST accepts, such as P7D or PT15M
+var isoDurationPattern = regexp.MustCompile(`^P(\d+D)?(T(\d+H)?(\d+M)?(\d+S)?)?$`)
+
+/*
+WithQueryValidation - Validate every query before sending it
+Invalid queries fail with a *QueryProblemsError without spending a request of the rate limit.
+*/
+func WithQueryValidation() Option {
+	return func(c *Client) {
+		c.validateQuery = true
+	}
+}
+
+// IsKnown - Whether t is one of the Term constants
+func (t Term) IsKnown() bool {
+	_, ok := termKinds[t]
+	return ok
+}
+
+/*
+Validate - Check the query before it is sent, returning the problems FFS would report
+It checks terms, operator/term compatibility, timestamp, duration, numeric and boolean values,
+clauses, the page size and number and the sort settings. A valid query returns nil.
+*/
+func (q Query) Validate() []QueryProblem {
+	var problems []QueryProblem
+
+	problem := func(problemType string, filter SearchFilter, description string) {
+		problems = append(problems, QueryProblem{BadFilter: filter, Type: problemType, Description: description})
+	}
+
+	if len(q.Groups) == 0 {
+		problem(ProblemNoGroups, SearchFilter{}, "query has no groups")
+	}
+
+	if !validClause(q.GroupClause) {
+		problem(ProblemInvalidClause, SearchFilter{}, "groupClause must be AND or OR, not "+q.GroupClause)
+	}
+
+	for i, group := range q.Groups {
+		if len(group.Filters) == 0 {
+			problem(ProblemEmptyGroup, SearchFilter{}, "group "+strconv.Itoa(i)+" has no filters")
+		}
+
+		if !validClause(group.FilterClause) {
+			problem(ProblemInvalidClause, SearchFilter{}, "filterClause of group "+strconv.Itoa(i)+" must be AND or OR, not "+group.FilterClause)
+		}
+
+		for _, filter := range group.Filters {
+			problemType, description := validateFilter(filter)
+
+			if problemType != "" {
+				problem(problemType, filter, description)
+			}
+		}
+	}
+
+	if q.PgSize < 0 || q.PgSize > MaxPageSize {
+		problem(ProblemInvalidPageSize, SearchFilter{}, "pgSize must be 0 to "+strconv.Itoa(MaxPageSize)+" (0 uses the server default), not "+strconv.Itoa(q.PgSize))
+	}
+
+	if q.PgNum < 0 {
+		problem(ProblemInvalidPageNumber, SearchFilter{}, "pgNum must be positive, not "+strconv.Itoa(q.PgNum))
+	}
+
+	if q.SrtKey != "" && !Term(q.SrtKey).IsKnown() {
+		problem(ProblemInvalidSortKey, SearchFilter{}, "srtKey "+q.SrtKey+" is not a known term")
+	}
+
+	if q.SrtDir != "" && !strings.EqualFold(q.SrtDir, string(SortAsc)) && !strings.EqualFold(q.SrtDir, string(SortDesc)) {
+		problem(ProblemInvalidSortDir, SearchFilter{}, "srtDir must be asc or desc, not "+q.SrtDir)
+	}
+
+	return problems
+}
+
+func validClause(clause string) bool {
+	return clause == "" || clause == string(ClauseAnd) || clause == string(ClauseOr)
+}
+
+// validateFilter returns the type and description of the filter's problem, or "" if it is valid
+func validateFilter(filter SearchFilter) (string, string) {
+	term := Term(filter.Term)
+	kind, ok := termKinds[term]
+
+	if !ok {
+		return ProblemUnknownTerm, "unknown term " + filter.Term
+	}
+
+	switch Operator(filter.Operator) {
+	case OperatorExists, OperatorDoesNotExist:
+		return "", ""
+	case OperatorIs, OperatorIsNot:
+		if filter.Value == "" {
+			return ProblemInvalidValue, filter.Operator + " needs a value"
+		}
+	case OperatorOnOrAfter, OperatorOnOrBefore, OperatorWithinTheLast:
+		if kind != timestampTerm {
+			return ProblemInvalidOperator, filter.Operator + " only applies to timestamp terms, not " + filter.Term
+		}
+	case OperatorGreaterThan, OperatorLessThan:
+		if kind != numericTerm {
+			return ProblemInvalidOperator, filter.Operator + " only applies to numeric terms, not " + filter.Term
+		}
+	default:
+		return ProblemUnknownOperator, "unknown operator " + filter.Operator
+	}
+
+	if Operator(filter.Operator) == OperatorWithinTheLast {
+		if filter.Value == "P" || filter.Value == "PT" || strings.HasSuffix(filter.Value, "T") || !isoDurationPattern.MatchString(filter.Value) {
+			return ProblemInvalidValue, filter.Value + " is not an ISO 8601 duration such as P7D or PT1H"
+		}
+
+		return "", ""
+	}
+
+	switch kind {
+	case timestampTerm:
+		if _, err := ParseTimestamp(filter.Value); err != nil {
+			return ProblemInvalidValue, filter.Value + " is not a timestamp such as 2020-01-02T03:04:05.000Z"
+		}
+	case numericTerm:
+		if _, err := strconv.ParseInt(filter.Value, 10, 64); err != nil {
+			return ProblemInvalidValue, filter.Value + " is not a whole number"
+		}
+	case booleanTerm:
+		if filter.Value != "true" && filter.Value != "false" {
+			return ProblemInvalidValue, filter.Value + " is not true or false"
+		}
+	}
+
+	return "", ""
+}
@@ -0,0 +1,129 @@
+package ffs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryValidateValidQueries(t *testing.T) {
+	built := NewQueryBuilder().
+		Any(EventTypeIs(EventTypeCreated), TermFileName.Is("*.xlsx")).
+		All(TermFileSize.GreaterThan(10), TermShared.Is("true"), TermTrusted.DoesNotExist()).
+		WithinTheLast(TermInsertionTimestamp, 15*time.Minute).
+		Between(TermEventTimestamp, time.Now().Add(-time.Hour), time.Now()).
+		SortBy(TermEventTimestamp, SortDesc).
+		PageSize(MaxPageSize).
+		Build()
+
+	for _, query := range []Query{jsonQuery, built} {
+		if problems := query.Validate(); problems != nil {
+			t.Errorf("unexpected problems: %+v", problems)
+		}
+	}
+}
+
+func TestQueryValidateProblems(t *testing.T) {
+	tests := []struct {
+		filter      SearchFilter
+		problemType string
+	}{
+		{TermFileName.Is(""), ProblemInvalidValue},
+		{SearchFilter{Operator: "IS", Term: "filename", Value: "a"}, ProblemUnknownTerm},
+		{SearchFilter{Operator: "STARTS_WITH", Term: "fileName", Value: "a"}, ProblemUnknownOperator},
+		{SearchFilter{Operator: "ON_OR_AFTER", Term: "fileName", Value: "2020-01-01T00:00:00.000Z"}, ProblemInvalidOperator},
+		{SearchFilter{Operator: "GREATER_THAN", Term: "eventTimestamp", Value: "1"}, ProblemInvalidOperator},
+		{SearchFilter{Operator: "ON_OR_AFTER", Term: "eventTimestamp", Value: "last tuesday"}, ProblemInvalidValue},
+		{SearchFilter{Operator: "WITHIN_THE_LAST", Term: "eventTimestamp", Value: "7 days"}, ProblemInvalidValue},
+		{SearchFilter{Operator: "WITHIN_THE_LAST", Term: "eventTimestamp", Value: "PT"}, ProblemInvalidValue},
+		{SearchFilter{Operator: "LESS_THAN", Term: "fileSize", Value: "1kb"}, ProblemInvalidValue},
+		{SearchFilter{Operator: "IS", Term: "trusted", Value: "yes"}, ProblemInvalidValue},
+	}
+
+	for _, test := range tests {
+		query := Query{Groups: []Group{{Filters: []SearchFilter{test.filter}}}}
+		problems := query.Validate()
+
+		if len(problems) != 1 || problems[0].Type != test.problemType || problems[0].BadFilter != test.filter {
+			t.Errorf("%+v: unexpected problems %+v", test.filter, problems)
+		}
+	}
+
+	query := Query{
+		Groups:      []Group{{FilterClause: "XOR"}},
+		GroupClause: "and",
+		PgSize:      MaxPageSize + 1,
+		PgNum:       -1,
+		SrtKey:      "size",
+		SrtDir:      "up",
+	}
+
+	var types []string
+
+	for _, problem := range query.Validate() {
+		types = append(types, problem.Type)
+	}
+
+	want := []string{ProblemInvalidClause, ProblemEmptyGroup, ProblemInvalidClause, ProblemInvalidPageSize, ProblemInvalidPageNumber, ProblemInvalidSortKey, ProblemInvalidSortDir}
+
+	if len(types) != len(want) {
+		t.Fatalf("unexpected problems %v", types)
+	}
+
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("problem %d: got %s, want %s", i, types[i], want[i])
+		}
+	}
+
+	if problems := (Query{}).Validate(); len(problems) != 1 || problems[0].Type != ProblemNoGroups {
+		t.Errorf("unexpected problems for an empty query: %+v", problems)
+	}
+}
+
+func TestTermsCoverJsonFileEventFields(t *testing.T) {
+	for name := range knownJsonFields {
+		if name == "fielderrors" || name == "tabs" {
+			continue
+		}
+
+		found := false
+
+		for term := range termKinds {
+			if name == strings.ToLower(string(term)) {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Errorf("no term for field %s", name)
+		}
+	}
+}
+
+func TestClientQueryValidation(t *testing.T) {
+	requests := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL), WithQueryValidation())
+
+	_, _, err := c.GetJsonFileEventsContext(context.Background(), AuthData{AccessToken: "token"}, Query{}, "")
+
+	var problemsErr *QueryProblemsError
+
+	if !errors.As(err, &problemsErr) || problemsErr.Problems[0].Type != ProblemNoGroups {
+		t.Errorf("expected a *QueryProblemsError, got %v", err)
+	}
+
+	if requests != 0 {
+		t.Errorf("an invalid query was sent %d times", requests)
+	}
+}
@@ -0,0 +1,133 @@
+package ffs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FFS Rate Limiting
+
+// DefaultQueriesPerMinute - Code42 FFS API limit, additional queries are dropped
+const DefaultQueriesPerMinute = 120
+
+/*
+DefaultRateLimiter - Limiter shared by every Client created without WithRateLimiter
+Because it is package wide, parallel callers in one process coordinate automatically, set it to nil to disable limiting.
+*/
+var DefaultRateLimiter = NewRateLimiter(DefaultQueriesPerMinute, 1)
+
+/*
+RateLimiter - Thread-safe token bucket limiting how many requests are issued
+Tokens are added at perMinute/60 per second up to burst, every request takes one token
+and waits for it when the bucket is empty.
+*/
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+	stats    RateLimiterStats
+}
+
+// RateLimiterStats - Counters describing how much a RateLimiter has slowed requests down
+type RateLimiterStats struct {
+	//Requests is the number of requests which went through the limiter
+	Requests int64
+	//Delayed is the number of requests which had to wait for a token
+	Delayed int64
+	//TotalWait is the time spent waiting, summed across all requests
+	TotalWait time.Duration
+	//MaxWait is the longest single wait
+	MaxWait time.Duration
+}
+
+// NewRateLimiter - Create a RateLimiter allowing perMinute requests per minute with bursts of up to burst requests
+func NewRateLimiter(perMinute int, burst int) *RateLimiter {
+	if perMinute < 1 {
+		perMinute = 1
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		interval: time.Minute / time.Duration(perMinute),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		now:      time.Now,
+	}
+}
+
+// WithRateLimiter - Share limiter between every request the client issues, nil disables rate limiting
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// Wait - Block until a request may be issued, returns ctx.Err() if ctx is done first
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+
+	now := l.now()
+
+	//Refill the bucket for the time since the last request
+	if !l.last.IsZero() {
+		l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+
+	l.last = now
+
+	//Reserve a token, a negative balance is the queue of waiting requests
+	l.tokens--
+	l.stats.Requests++
+
+	var wait time.Duration
+
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens * float64(l.interval))
+		l.stats.Delayed++
+		l.stats.TotalWait += wait
+
+		if wait > l.stats.MaxWait {
+			l.stats.MaxWait = wait
+		}
+	}
+
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		//Give the reserved token back so later requests are not delayed by it
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// Stats - Snapshot of the limiter's counters
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.stats
+}
@@ -0,0 +1,79 @@
+package ffs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	//600 per minute is one token every 100ms
+	limiter := NewRateLimiter(600, 2)
+
+	start := time.Now()
+
+	for i := 0; i < 4; i++ {
+		err := limiter.Wait(context.Background())
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	//Two requests fit in the burst, the other two wait ~100ms each
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("expected requests to be delayed, took %s", elapsed)
+	}
+
+	stats := limiter.Stats()
+
+	if stats.Requests != 4 || stats.Delayed != 2 || stats.TotalWait < 150*time.Millisecond || stats.MaxWait < 50*time.Millisecond {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestRateLimiterContext(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+
+	_ = limiter.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestClientRateLimiterSharedByPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query Query
+		_ = json.NewDecoder(r.Body).Decode(&query)
+
+		response := JsonFileEventResponse{FileEvents: []JsonFileEvent{{EventId: "1"}}}
+
+		if query.PgToken == "" {
+			response.NextPgToken = "page2"
+		}
+
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(60000, 10)
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL), WithRateLimiter(limiter))
+
+	_, _, err := c.GetJsonFileEvents(AuthData{AccessToken: "token"}, jsonQuery, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if limiter.Stats().Requests != 2 {
+		t.Errorf("expected both pages to go through the limiter, got %d", limiter.Stats().Requests)
+	}
+}
@@ -0,0 +1,160 @@
+package ffs
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Request Retries
+
+/*
+RetryPolicy - Controls how failed requests are retried
+Requests failing with a retryable status code or a "Service Under Maintenance" response are retried
+with exponential backoff: InitialBackoff * Multiplier^n, capped at MaxBackoff, randomised by +/- Jitter.
+A Retry-After header sent with the response takes precedence over the computed backoff.
+*/
+type RetryPolicy struct {
+	//MaxAttempts is the total number of attempts including the first one, 1 or less disables retries
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	//Jitter is the fraction (0 to 1) of each backoff which is randomised
+	Jitter float64
+	//RetryableStatusCodes defaults to 429, 502, 503 and 504 when empty
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy - Retry policy used by a Client created without WithRetryPolicy
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// NoRetry - Retry policy which makes every request exactly once
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+var defaultRetryableStatusCodes = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// WithRetryPolicy - Retry auth and every search page according to policy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// retryable reports whether err is worth another attempt
+func (p RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, ErrMaintenance) {
+		return true
+	}
+
+	var httpErr *HTTPError
+
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+
+	statusCodes := p.RetryableStatusCodes
+
+	if len(statusCodes) == 0 {
+		statusCodes = defaultRetryableStatusCodes
+	}
+
+	for _, statusCode := range statusCodes {
+		if httpErr.StatusCode == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff returns the delay before retry number attempt (starting at 1)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (rand.Float64()*2 - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+/*
+retryAfter parses the Retry-After header of an HTTPError
+The header is either a number of seconds or an HTTP date
+*/
+func retryAfter(err error, now time.Time) (time.Duration, bool) {
+	var httpErr *HTTPError
+
+	if !errors.As(err, &httpErr) || httpErr.Header == nil {
+		return 0, false
+	}
+
+	value := httpErr.Header.Get("Retry-After")
+
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// withRetry calls attempt until it succeeds, fails with a non retryable error, attempts run out or ctx is done
+func (c *Client) withRetry(ctx context.Context, op string, attempt func() error) error {
+	for n := 1; ; n++ {
+		err := attempt()
+
+		if err == nil || n >= c.retryPolicy.MaxAttempts || !c.retryPolicy.retryable(err) {
+			return err
+		}
+
+		delay, ok := retryAfter(err, time.Now())
+
+		if !ok {
+			delay = c.retryPolicy.backoff(n)
+		}
+
+		c.logf("Retrying %s in %s after attempt %d failed: %v", op, delay, n, err)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
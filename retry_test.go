@@ -0,0 +1,145 @@
+package ffs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetryPolicy = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond, Multiplier: 2}
+
+func TestRetryTransientPage(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			_ = json.NewEncoder(w).Encode(JsonFileEventResponse{FileEvents: []JsonFileEvent{{EventId: "1"}}, NextPgToken: "page2"})
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 3:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			_ = json.NewEncoder(w).Encode(JsonFileEventResponse{FileEvents: []JsonFileEvent{{EventId: "2"}}})
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL), WithRetryPolicy(fastRetryPolicy))
+
+	events, _, err := c.GetJsonFileEvents(AuthData{AccessToken: "token"}, jsonQuery, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*events) != 2 || requests != 4 {
+		t.Errorf("expected 2 events over 4 requests, got %d events over %d requests", len(*events), requests)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL), WithRetryPolicy(fastRetryPolicy))
+
+	_, _, err := c.GetJsonFileEvents(AuthData{AccessToken: "token"}, jsonQuery, "")
+
+	var httpErr *HTTPError
+
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected a 503 HTTPError, got %v", err)
+	}
+
+	if requests != 3 {
+		t.Errorf("expected 3 attempts, got %d", requests)
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL), WithRetryPolicy(fastRetryPolicy))
+
+	_, _, err := c.GetJsonFileEvents(AuthData{AccessToken: "token"}, jsonQuery, "")
+
+	if err == nil || requests != 1 {
+		t.Errorf("expected a single failed attempt, got %d attempts and %v", requests, err)
+	}
+}
+
+func TestRetryAuthMaintenance(t *testing.T) {
+	var requests int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			_, _ = w.Write([]byte("Service Under Maintenance"))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"access_token":"token"}`))
+	}))
+	defer server.Close()
+
+	authData, err := NewClient(WithAuthURL(server.URL), WithRetryPolicy(fastRetryPolicy)).GetAuthData("user", "pass")
+
+	if err != nil || authData.AccessToken != "token" {
+		t.Errorf("expected a token after the maintenance page, got %v, %v", authData, err)
+	}
+}
+
+func TestRetryContextCancelledDuringBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, _, err := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL)).GetJsonFileEventsContext(ctx, AuthData{AccessToken: "token"}, jsonQuery, "")
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second, Multiplier: 2, Jitter: 0.5}
+
+	for attempt, base := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		delay := policy.backoff(attempt + 1)
+
+		if delay < base/2 || delay > base*3/2 {
+			t.Errorf("attempt %d: backoff %s outside %s +/- 50%%", attempt+1, delay, base)
+		}
+	}
+
+	header := http.Header{}
+	header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+
+	delay, ok := retryAfter(&HTTPError{Header: header}, time.Now())
+
+	if !ok || delay <= 55*time.Second || delay > time.Minute {
+		t.Errorf("expected a ~1m Retry-After from an HTTP date, got %s", delay)
+	}
+}
@@ -0,0 +1,136 @@
+package ffs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// FFS Timestamps
+
+// timestampLayouts are the string formats accepted for FFS timestamps, zoneless values are UTC
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+/*
+Timestamp - A file event timestamp from the FFS JSON API
+It unmarshals RFC 3339 strings (with or without a zone, zoneless values are UTC) and epoch milliseconds,
+and marshals back to exactly the value it was decoded from as long as Time is unchanged. Changed timestamps
+and timestamps created with NewTimestamp marshal in the millisecond UTC format FFS uses.
+*/
+type Timestamp struct {
+	time.Time
+	//raw is the JSON the timestamp was decoded from, valid while Time still equals parsed
+	raw    string
+	parsed time.Time
+}
+
+// NewTimestamp - A Timestamp for t
+func NewTimestamp(t time.Time) *Timestamp {
+	return &Timestamp{Time: t}
+}
+
+// ParseTimestamp - Parse a timestamp in one of the formats the FFS API emits
+func ParseTimestamp(value string) (*Timestamp, error) {
+	for _, layout := range timestampLayouts {
+		t, err := time.Parse(layout, value)
+
+		if err == nil {
+			return &Timestamp{Time: t, raw: strconv.Quote(value), parsed: t}, nil
+		}
+	}
+
+	return nil, errors.New("invalid timestamp: " + value)
+}
+
+// original returns the JSON the timestamp was decoded from, "" if there is none or Time was changed since
+func (t Timestamp) original() string {
+	if t.raw == "" || !t.Time.Equal(t.parsed) {
+		return ""
+	}
+
+	return t.raw
+}
+
+// String - The value the timestamp was parsed from, or the millisecond UTC format for changed timestamps and those created with NewTimestamp
+func (t Timestamp) String() string {
+	if raw := t.original(); raw != "" {
+		value, err := strconv.Unquote(raw)
+
+		if err == nil {
+			return value
+		}
+
+		return raw
+	}
+
+	return t.UTC().Format(ffsTimestampFormat)
+}
+
+// MarshalJSON - Re-emit the original value, or the millisecond UTC format for changed timestamps and those created with NewTimestamp
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if raw := t.original(); raw != "" {
+		return []byte(raw), nil
+	}
+
+	return json.Marshal(t.UTC().Format(ffsTimestampFormat))
+}
+
+// UnmarshalJSON - Accept a string in one of the FFS formats or a number of epoch milliseconds
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+
+		err := json.Unmarshal(data, &value)
+
+		if err != nil {
+			return err
+		}
+
+		parsed, err := ParseTimestamp(value)
+
+		if err != nil {
+			return err
+		}
+
+		t.Time = parsed.Time
+		t.raw = string(data)
+		t.parsed = parsed.Time
+
+		return nil
+	}
+
+	millis, err := strconv.ParseInt(string(data), 10, 64)
+
+	if err != nil {
+		return errors.New("invalid timestamp: " + string(data))
+	}
+
+	t.Time = time.Unix(0, millis*int64(time.Millisecond)).UTC()
+	t.raw = string(data)
+	t.parsed = t.Time
+
+	return nil
+}
+
+// timestampTime returns the time of t, truncated to seconds for the CSV file timestamps
+func timestampTime(t *Timestamp, seconds bool) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	value := t.Time
+
+	if seconds {
+		value = value.Truncate(time.Second)
+	}
+
+	return &value
+}
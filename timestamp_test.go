@@ -0,0 +1,119 @@
+package ffs
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+)
+
+// mustParseTimestamp parses value or fails the test
+func mustParseTimestamp(t *testing.T, value string) *Timestamp {
+	t.Helper()
+
+	timestamp, err := ParseTimestamp(value)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return timestamp
+}
+
+func TestTimestampRoundTrip(t *testing.T) {
+	tests := map[string]time.Time{
+		`"2020-03-04T05:06:07.123Z"`:    time.Date(2020, 3, 4, 5, 6, 7, 123000000, time.UTC),
+		`"2020-03-04T05:06:07Z"`:        time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC),
+		`"2020-03-04T06:06:07.5+01:00"`: time.Date(2020, 3, 4, 5, 6, 7, 500000000, time.UTC),
+		`"2020-03-04T05:06:07.123456"`:  time.Date(2020, 3, 4, 5, 6, 7, 123456000, time.UTC),
+		`"2020-03-04 05:06:07"`:         time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC),
+		`1583298367123`:                 time.Date(2020, 3, 4, 5, 6, 7, 123000000, time.UTC),
+	}
+
+	for data, want := range tests {
+		var event JsonFileEvent
+
+		err := json.Unmarshal([]byte(`{"eventId":"a","eventTimestamp":`+data+`}`), &event)
+
+		if err != nil {
+			t.Errorf("%s: %v", data, err)
+			continue
+		}
+
+		if !event.EventTimestamp.Equal(want) {
+			t.Errorf("%s: got %v, want %v", data, event.EventTimestamp.Time, want)
+		}
+
+		out, err := json.Marshal(event)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(out) != `{"eventId":"a","eventTimestamp":`+data+`}` {
+			t.Errorf("%s was re-serialized as %s", data, out)
+		}
+	}
+}
+
+func TestTimestampInvalid(t *testing.T) {
+	for _, data := range []string{`"yesterday"`, `true`, `"2020-13-01T00:00:00Z"`} {
+		var event JsonFileEvent
+
+		if err := json.Unmarshal([]byte(`{"eventTimestamp":`+data+`}`), &event); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+
+	var event JsonFileEvent
+
+	if err := json.Unmarshal([]byte(`{"eventTimestamp":null}`), &event); err != nil || event.EventTimestamp != nil {
+		t.Errorf("null timestamp: %v %v", err, event.EventTimestamp)
+	}
+}
+
+func TestNewTimestamp(t *testing.T) {
+	timestamp := NewTimestamp(time.Date(2020, 3, 4, 6, 6, 7, 0, time.FixedZone("CET", 3600)))
+
+	out, err := json.Marshal(timestamp)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(out) != `"2020-03-04T05:06:07.000Z"` || timestamp.String() != "2020-03-04T05:06:07.000Z" {
+		t.Errorf("unexpected format: %s %s", out, timestamp)
+	}
+
+	//The embedded time.Time sorts and compares
+	timestamps := []*Timestamp{timestamp, mustParseTimestamp(t, "2020-03-04T05:06:06Z")}
+
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j].Time) })
+
+	if timestamps[0].String() != "2020-03-04T05:06:06Z" {
+		t.Errorf("unexpected order: %v", timestamps)
+	}
+}
+
+func TestTimestampChangedTime(t *testing.T) {
+	timestamp := mustParseTimestamp(t, "2020-01-01T00:00:00Z")
+
+	//A different zone for the same instant keeps the original value
+	timestamp.Time = timestamp.In(time.FixedZone("CET", 3600))
+
+	if timestamp.String() != "2020-01-01T00:00:00Z" {
+		t.Errorf("unexpected value for an unchanged instant: %s", timestamp)
+	}
+
+	timestamp.Time = timestamp.Add(time.Hour)
+
+	out, err := json.Marshal(timestamp)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(out) != `"2020-01-01T01:00:00.000Z"` || timestamp.String() != "2020-01-01T01:00:00.000Z" {
+		t.Errorf("changed timestamp marshalled as %s %s", out, timestamp)
+	}
+}
@@ -0,0 +1,109 @@
+package ffs
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Code42 Token Caching
+
+const (
+	// DefaultTokenLifetime - Lifetime assumed for AuthData which does not carry ExpiresIn, Code42 tokens last 1 hour
+	DefaultTokenLifetime = time.Hour
+	// DefaultRefreshBefore - How long before expiry a CachingTokenSource fetches a new token
+	DefaultRefreshBefore = 5 * time.Minute
+)
+
+// TokenSource - Supplies the AuthData used to authorize FFS requests
+type TokenSource interface {
+	Token(ctx context.Context) (*AuthData, error)
+}
+
+// AuthFunc - Fetches fresh AuthData, for example by calling Client.GetAuthDataContext
+type AuthFunc func(ctx context.Context) (*AuthData, error)
+
+/*
+CachingTokenSource - Thread-safe TokenSource which caches AuthData until shortly before it expires
+It is meant to be shared by every concurrent query, only one refresh is in flight at any time and
+callers waiting on that refresh receive the same AuthData.
+*/
+type CachingTokenSource struct {
+	fetch         AuthFunc
+	refreshBefore time.Duration
+	now           func() time.Time
+
+	//sem is a one slot semaphore guarding the fields below, a channel is used so waiting respects ctx
+	sem      chan struct{}
+	authData *AuthData
+	expiry   time.Time
+}
+
+// NewCachingTokenSource - Create a CachingTokenSource fetching tokens with fetch, refreshing refreshBefore ahead of expiry
+func NewCachingTokenSource(fetch AuthFunc, refreshBefore time.Duration) *CachingTokenSource {
+	return &CachingTokenSource{
+		fetch:         fetch,
+		refreshBefore: refreshBefore,
+		now:           time.Now,
+		sem:           make(chan struct{}, 1),
+	}
+}
+
+// PasswordTokenSource - CachingTokenSource which authenticates against the client's auth URL with username and password
+func (c *Client) PasswordTokenSource(username string, password string) *CachingTokenSource {
+	return NewCachingTokenSource(func(ctx context.Context) (*AuthData, error) {
+		return c.GetAuthDataContext(ctx, username, password)
+	}, DefaultRefreshBefore)
+}
+
+// Token - Return the cached AuthData, fetching a new one if none is cached or it is about to expire
+func (ts *CachingTokenSource) Token(ctx context.Context) (*AuthData, error) {
+	select {
+	case ts.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-ts.sem }()
+
+	if ts.authData != nil && ts.now().Before(ts.expiry.Add(-ts.refreshBefore)) {
+		return ts.authData, nil
+	}
+
+	authData, err := ts.fetch(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if authData.AccessToken == "" {
+		if authData.Error != "" {
+			return nil, errors.New("error getting auth token: " + authData.Error)
+		}
+
+		return nil, errors.New("auth response did not contain an access token")
+	}
+
+	lifetime := DefaultTokenLifetime
+
+	if authData.ExpiresIn != nil {
+		lifetime = time.Duration(*authData.ExpiresIn) * time.Second
+	}
+
+	ts.authData = authData
+	ts.expiry = ts.now().Add(lifetime)
+
+	return authData, nil
+}
+
+/*
+Invalidate - Drop the cached AuthData so the next Token call refreshes it
+authData is the token which was rejected, if the cache was already refreshed by another caller it is left alone
+*/
+func (ts *CachingTokenSource) Invalidate(authData *AuthData) {
+	ts.sem <- struct{}{}
+	defer func() { <-ts.sem }()
+
+	if authData == nil || ts.authData == nil || ts.authData.AccessToken == authData.AccessToken {
+		ts.authData = nil
+	}
+}
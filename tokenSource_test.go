@@ -0,0 +1,119 @@
+package ffs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCachingTokenSourceRefresh(t *testing.T) {
+	var fetches int32
+	expiresIn := 3600
+
+	ts := NewCachingTokenSource(func(ctx context.Context) (*AuthData, error) {
+		n := atomic.AddInt32(&fetches, 1)
+		return &AuthData{AccessToken: "token" + strconv.Itoa(int(n)), ExpiresIn: &expiresIn}, nil
+	}, DefaultRefreshBefore)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts.now = func() time.Time { return now }
+
+	authData, err := ts.Token(context.Background())
+
+	if err != nil || authData.AccessToken != "token1" {
+		t.Fatalf("unexpected token %v, %v", authData, err)
+	}
+
+	//Still well within the lifetime, the cached token must be reused
+	now = now.Add(30 * time.Minute)
+	authData, _ = ts.Token(context.Background())
+
+	if authData.AccessToken != "token1" {
+		t.Error("expected cached token, got " + authData.AccessToken)
+	}
+
+	//Inside the refresh window, a new token must be fetched
+	now = now.Add(26 * time.Minute)
+	authData, _ = ts.Token(context.Background())
+
+	if authData.AccessToken != "token2" {
+		t.Error("expected refreshed token, got " + authData.AccessToken)
+	}
+
+	//Invalidating a stale token must not drop the current one
+	ts.Invalidate(&AuthData{AccessToken: "token1"})
+	authData, _ = ts.Token(context.Background())
+
+	if authData.AccessToken != "token2" {
+		t.Error("expected token2 to survive a stale invalidation, got " + authData.AccessToken)
+	}
+}
+
+func TestCachingTokenSourceConcurrent(t *testing.T) {
+	var fetches int32
+
+	ts := NewCachingTokenSource(func(ctx context.Context) (*AuthData, error) {
+		atomic.AddInt32(&fetches, 1)
+		time.Sleep(10 * time.Millisecond)
+		return &AuthData{AccessToken: "token"}, nil
+	}, DefaultRefreshBefore)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := ts.Token(context.Background())
+
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if fetches != 1 {
+		t.Errorf("expected a single fetch, got %d", fetches)
+	}
+}
+
+func TestClientTokenSourceUnauthorizedRetry(t *testing.T) {
+	var fetches int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		//Only the second token is accepted, as if the first one was revoked early
+		if r.Header.Get("Authorization") != "Bearer token2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(JsonFileEventResponse{FileEvents: []JsonFileEvent{{EventId: "1"}}})
+	}))
+	defer server.Close()
+
+	ts := NewCachingTokenSource(func(ctx context.Context) (*AuthData, error) {
+		n := atomic.AddInt32(&fetches, 1)
+		return &AuthData{AccessToken: "token" + strconv.Itoa(int(n))}, nil
+	}, DefaultRefreshBefore)
+
+	c := NewClient(WithHTTPClient(server.Client()), WithFFSURL(server.URL), WithTokenSource(ts))
+
+	events, _, err := c.GetJsonFileEvents(AuthData{}, jsonQuery, "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*events) != 1 || fetches != 2 {
+		t.Errorf("expected 1 event after 2 token fetches, got %d events and %d fetches", len(*events), fetches)
+	}
+}